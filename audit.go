@@ -1,56 +1,41 @@
 package audit
 
 import (
+	"time"
+
 	"github.com/luraproject/lura/v2/config"
 )
 
 // Audit audits the received configuration and generates an AuditResult with all the Recommendations
+// using the default Auditor
 func Audit(cfg *config.ServiceConfig, ignore, severities []string) (AuditResult, error) {
-	service := Parse(cfg)
-
-	res := AuditResult{Recommendations: []Recommendation{}}
-	keysToIgnore := map[string]struct{}{}
-	for _, k := range ignore {
-		keysToIgnore[k] = struct{}{}
-	}
-	severitiesToCatch := map[string]struct{}{}
-	for _, k := range severities {
-		severitiesToCatch[k] = struct{}{}
+	a, err := NewAuditor()
+	if err != nil {
+		return AuditResult{}, err
 	}
+	return a.Audit(cfg, ignore, severities)
+}
 
-	for i := range ruleSet {
-		if _, ok := keysToIgnore[ruleSet[i].Recommendation.Rule]; ok {
-			continue
-		}
-
-		if _, ok := severitiesToCatch[ruleSet[i].Recommendation.Severity]; !ok {
-			continue
-		}
-
-		if ruleSet[i].Evaluate(&service) {
-			res.Recommendations = append(res.Recommendations, ruleSet[i].Recommendation)
-		}
+// Run audits the received configuration with the default Auditor and the received options
+func Run(cfg *config.ServiceConfig, opts ...Option) (AuditResult, error) {
+	a, err := NewAuditor()
+	if err != nil {
+		return AuditResult{}, err
 	}
-
-	return res, nil
+	return a.Run(cfg, opts...)
 }
 
-const (
-	SeverityCritical = "CRITICAL"
-	SeverityHigh     = "HIGH"
-	SeverityMedium   = "MEDIUM"
-	SeverityLow      = "LOW"
-)
-
-// Rule encapsulates a recommendation and an evaluation function that determines if the recommendation
-// applies for a given service definition
+// Rule encapsulates a recommendation and an evaluation function that returns the scopes of the
+// service definition where the recommendation applies. The recommendation does not apply when
+// no scopes are returned. Metadata documents the rule in the catalog
 type Rule struct {
 	Recommendation Recommendation
-	Evaluate       func(*Service) bool
+	Evaluate       func(*Service) []Scope
+	Metadata       RuleMetadata
 }
 
 // NewRule creates a Rule with the given arguments
-func NewRule(id, severity, msg string, ef func(*Service) bool) Rule {
+func NewRule(id string, severity Severity, msg string, ef func(*Service) []Scope) Rule {
 	return Rule{
 		Recommendation: Recommendation{
 			Rule:     id,
@@ -61,21 +46,67 @@ func NewRule(id, severity, msg string, ef func(*Service) bool) Rule {
 	}
 }
 
-// AuditResult contains all the recommendations and stats generated by the audit process
+// AuditResult contains all the recommendations and stats generated by the audit process.
+// The findings acknowledged by the suppressions declared in the configuration are reported
+// apart, along with their justification. Baseline is only set when the audit is compared
+// with a previous result
 type AuditResult struct {
-	Recommendations []Recommendation `json:"recommendations"`
-	Stats           Stats            `json:"stats"`
+	Recommendations []Recommendation           `json:"recommendations"`
+	Suppressed      []SuppressedRecommendation `json:"suppressed"`
+	Baseline        *BaselineDiff              `json:"baseline,omitempty"`
+	Score           Score                      `json:"score"`
+	Stats           Stats                      `json:"stats"`
 }
 
-// Recommendation maps a rule id with a severity and a message
+// Recommendation maps a rule id with a severity, a message and the locations where it applies.
+// OriginalSeverity is only set when the severity of the rule has been overridden
 type Recommendation struct {
-	Rule     string `json:"rule"`
-	Severity string `json:"severity"`
-	Message  string `json:"message"`
+	Rule             string     `json:"rule"`
+	Severity         Severity   `json:"severity"`
+	OriginalSeverity Severity   `json:"original_severity,omitempty"`
+	Message          string     `json:"message"`
+	Locations        []Location `json:"locations,omitempty"`
 }
 
-// Stats is an empty struct that will be completed in the future
-type Stats struct{}
+// Stats summarizes the audited configuration and the audit process. Modifiers counts the
+// response body modifiers declared in the configuration by kind
+type Stats struct {
+	Endpoints  int                       `json:"endpoints"`
+	Backends   int                       `json:"backends"`
+	Agents     int                       `json:"agents"`
+	Components map[string]ComponentStats `json:"components"`
+	Modifiers  map[string]int            `json:"modifiers"`
+	Findings   FindingStats              `json:"findings"`
+	Rules      RuleStats                 `json:"rules"`
+	Duration   time.Duration             `json:"duration"`
+}
+
+// ComponentStats counts how many times a component namespace is used at each scope.
+// Backends of the async agents are included in the backend counter
+type ComponentStats struct {
+	Service  int `json:"service"`
+	Endpoint int `json:"endpoint"`
+	Backend  int `json:"backend"`
+	Agent    int `json:"agent"`
+}
+
+// FindingStats counts the recommendations of the audit by severity and by section of the rule set.
+// Suppressed counts the recommendations acknowledged by the suppressions of the configuration
+type FindingStats struct {
+	Total      int              `json:"total"`
+	Locations  int              `json:"locations"`
+	Suppressed int              `json:"suppressed"`
+	BySeverity map[Severity]int `json:"by_severity"`
+	BySection  map[string]int   `json:"by_section"`
+}
+
+// RuleStats counts the rules registered, evaluated and skipped during the audit
+type RuleStats struct {
+	Total              int `json:"total"`
+	Evaluated          int `json:"evaluated"`
+	Ignored            int `json:"ignored"`
+	FilteredBySeverity int `json:"filtered_by_severity"`
+}
 
 var ruleSet = []Rule{
 	/*
@@ -84,6 +115,10 @@ var ruleSet = []Rule{
 	NewRule("1.1.1", SeverityHigh, "Implement more secure alternatives than Basic Auth to protect your data.", hasBasicAuth),
 	NewRule("1.1.2", SeverityMedium, "Implement stateless authorization methods such as JWT to secure your endpoints as opposed to using API keys.", hasApiKeys),
 	NewRule("1.2.1", SeverityHigh, "Prioritize using JWT for endpoint authorization to ensure security.", hasNoJWT),
+	NewRule("1.2.2", SeverityMedium, "Implement a token revocation mechanism (bloomfilter) to invalidate the JWT before they expire.", hasNoRevocation),
+	NewRule("1.2.3", SeverityHigh, "Declare the token_keys of the revocation bloomfilter, otherwise no token can be revoked.", hasRevocationWithoutTokenKeys),
+	NewRule("1.2.4", SeverityLow, "Avoid declaring a revocation bloomfilter when no endpoint validates JWT.", hasRevocationWithoutJWT),
+	NewRule("1.2.5", SeverityLow, "Use the optimal hash function in the revocation bloomfilter.", hasRevocationNonOptimalHash),
 
 	/*
 	   Section 2: Service level recommendations
@@ -91,15 +126,32 @@ var ruleSet = []Rule{
 	NewRule("2.1.1", SeverityHigh, "Only allow secure connections (avoid insecure_connections).", hasInsecureConnections),
 	NewRule("2.1.2", SeverityHigh, "Enable TLS or use a terminator in front of KrakenD.", hasNoTLS),
 	NewRule("2.1.3", SeverityCritical, "TLS is configured but its disable flag prevents from using it.", hasTLSDisabled),
+	NewRule("2.1.4", SeverityHigh, "Use TLS 1.2 or above as the minimum version.", hasOutdatedTLSVersion),
+	NewRule("2.1.5", SeverityHigh, "Avoid insecure cipher suites.", hasInsecureCipherSuites),
+	NewRule("2.1.6", SeverityMedium, "Allow cipher suites with authenticated encryption (AEAD) instead of CBC only.", hasCBCOnlyCipherSuites),
 	NewRule("2.1.7", SeverityHigh, "Enable HTTP security header checks (security/http).", hasNoHTTPSecure),
 	NewRule("2.1.8", SeverityHigh, "Avoid clear text communication (h2c).", hasH2C),
 	NewRule("2.1.9", SeverityLow, "Establish secure connections in internal traffic (avoid insecure_connections internally)", hasBackendInsecureConnections),
+	NewRule("2.1.10", SeverityHigh, "Do not trust the system CA pool when validating client certificates (mTLS).", hasMTLSWithSystemCaPool),
+	NewRule("2.1.11", SeverityHigh, "Declare the CA certificates used to validate the client certificates (mTLS).", hasMTLSWithoutCaCerts),
 	NewRule("2.2.1", SeverityMedium, "Hide the version banner in runtime.", hasNoObfuscatedVersionHeader),
 	NewRule("2.2.2", SeverityHigh, "Enable CORS.", hasNoCORS),
 	NewRule("2.2.3", SeverityHigh, "Avoid passing all input headers to the backend.", hasHeadersWildcard),
 	NewRule("2.2.4", SeverityHigh, "Avoid passing all input query strings to the backend.", hasQueryStringWildcard),
 	NewRule("2.2.5", SeverityLow, "Avoid exposing gRPC server without services declared.", hasEmptyGRPCServer),
 	NewRule("2.3.1", SeverityMedium, "Limit the amount of cacheable content.", hasUnlimitedCache),
+	NewRule("2.3.2", SeverityHigh, "Avoid shared caches on endpoints with authentication or forwarding the Authorization or Cookie headers.", hasSharedCacheOnAuthenticatedEndpoint),
+	NewRule("2.3.3", SeverityMedium, "Avoid long cache_ttl on endpoints with methods other than GET, HEAD or OPTIONS.", hasLongCacheTTLOnUnsafeMethod),
+	NewRule("2.3.4", SeverityMedium, "Avoid caching on no-op endpoints.", hasCacheOnNoopEndpoint),
+	NewRule("2.4.1", SeverityMedium, "Avoid returning the error messages of the backends to the clients (return_error_msg).", hasRouterErrorMsg),
+	NewRule("2.4.2", SeverityHigh, "Declare the trusted_proxies when forwarded_by_client_ip is enabled to prevent client IP spoofing.", hasUntrustedClientIP),
+	NewRule("2.4.3", SeverityMedium, "Keep the access log enabled unless another logging component is in place.", hasNoAccessLog),
+	NewRule("2.4.4", SeverityLow, "Keep the health endpoint enabled so orchestrators and load balancers can check the gateway.", hasRouterHealthOff),
+	NewRule("2.4.5", SeverityLow, "Avoid disabling the path decoding of the router.", hasRouterPathDecodeOff),
+	NewRule("2.5.1", SeverityMedium, "Use https to connect to the backends outside the loopback interface and the private ranges when the gateway terminates TLS.", hasPlaintextBackend),
+	NewRule("2.5.2", SeverityMedium, "Avoid mixing http and https hosts in the same backend.", hasMixedBackendSchemes),
+	NewRule("2.5.3", SeverityLow, "Avoid localhost backends unless the services run next to the gateway.", hasLoopbackBackend),
+	NewRule("2.5.4", SeverityLow, "Declare several hosts or a service discovery to balance the load of the backends.", hasSingleHostBackend),
 
 	/*
 	   Section 3: Traffic management / rate limits
@@ -107,10 +159,21 @@ var ruleSet = []Rule{
 	NewRule("3.1.1", SeverityLow, "Enable a bot detector.", hasBotdetectorDisabled),
 	NewRule("3.1.2", SeverityHigh, "Implement a rate-limiting strategy and avoid having an All-You-Can-Eat API.", hasNoRatelimit),
 	NewRule("3.1.3", SeverityHigh, "Protect your backends with a circuit breaker.", hasNoCB),
+	NewRule("3.1.4", SeverityMedium, "Rate limit every endpoint with methods that change data.", hasUnthrottledWriteEndpoint),
+	NewRule("3.1.5", SeverityHigh, "Declare a strategy for the client_max_rate, as the per-client limit is ignored without it.", hasClientRatelimitWithoutStrategy),
+	NewRule("3.1.6", SeverityHigh, "Declare the key of the header and param rate limit strategies.", hasClientRatelimitWithoutKey),
+	NewRule("3.1.7", SeverityLow, "Keep the client_max_rate below the max_rate of the whole endpoint.", hasClientRateAboveMaxRate),
+	NewRule("3.2.1", SeverityMedium, "Declare the user agents to allow, deny or match in the bot detector, otherwise it lets every request in.", hasInertBotDetector),
+	NewRule("3.2.2", SeverityLow, "Set a cache_size in bot detectors with many patterns to avoid evaluating them on every request.", hasUncachedBotDetector),
+	NewRule("3.2.3", SeverityLow, "Apply the bot detector consistently, at service level or on every endpoint.", hasInconsistentBotDetection),
 	NewRule("3.3.1", SeverityLow, "Set timeouts to below 3 seconds for improved performance.", hasTimeoutBiggerThan(3000)),
 	NewRule("3.3.2", SeverityMedium, "Set timeouts to below 5 seconds for improved performance.", hasTimeoutBiggerThan(5000)),
 	NewRule("3.3.3", SeverityHigh, "Set timeouts to below 30 seconds for improved performance.", hasTimeoutBiggerThan(30000)),
 	NewRule("3.3.4", SeverityCritical, "Set timeouts to below 1 minute for improved performance.", hasTimeoutBiggerThan(60000)),
+	NewRule("3.3.5", SeverityMedium, "Set a read_header_timeout to close the connections of slow clients.", hasNoReadHeaderTimeout),
+	NewRule("3.3.6", SeverityMedium, "Set a write_timeout longer than the timeout of the endpoints, or the responses are cut off.", hasShortWriteTimeout),
+	NewRule("3.3.7", SeverityMedium, "Set an idle_timeout to close the idle keep-alive connections.", hasUnboundedIdleConnections),
+	NewRule("3.3.8", SeverityLow, "Keep max_header_bytes at 1MB or below.", hasLargeMaxHeaderBytes),
 
 	/*
 	   Section 4 : Telemetry
@@ -118,8 +181,12 @@ var ruleSet = []Rule{
 	NewRule("4.1.1", SeverityMedium, "Implement a telemetry system for collecting metrics for monitoring and troubleshooting.", hasNoMetrics),
 	NewRule("4.1.2", SeverityMedium, "Give your configuration a name for easy identification in metric tracking.", hasTelemetryMissingName),
 	NewRule("4.1.3", SeverityHigh, "Avoid duplicating telemetry options to prevent system overload.", hasSeveralTelemetryComponents),
+	NewRule("4.1.4", SeverityLow, "Avoid reporting the OpenTelemetry metrics more often than every 10 seconds.", hasShortMetricReportingPeriod),
 	NewRule("4.2.1", SeverityMedium, "Implement a telemetry system for tracing for monitoring and troubleshooting.", hasNoTracing),
+	NewRule("4.2.2", SeverityMedium, "Avoid sampling all the traces in production (trace_sample_rate).", hasFullTraceSampling),
 	NewRule("4.3.1", SeverityMedium, "Use the improved logging component for better log parsing.", hasNoLogging),
+	NewRule("4.4.1", SeverityMedium, "Send the OpenTelemetry data to remote collectors over encrypted connections.", hasInsecureOTLPExporter),
+	NewRule("4.4.2", SeverityMedium, "Enable the metrics or the traces of at least one OpenTelemetry exporter.", hasAllOTELExportersDisabled),
 	/*
 	   Section 5: Endpoint level audit
 	*/
@@ -130,9 +197,30 @@ var ruleSet = []Rule{
 	NewRule("5.1.5", SeverityMedium, "Declare explicit endpoints instead of using /__catchall.", hasEndpointCatchAll),
 	NewRule("5.1.6", SeverityMedium, "Avoid using multiple write methods in endpoint definitions.", hasMultipleUnsafeMethods),
 	NewRule("5.1.7", SeverityMedium, "Avoid using sequential proxy.", hasSequentialProxy),
+	NewRule("5.1.8", SeverityHigh, "Avoid declaring the same method and path in several endpoints.", hasDuplicatedRoutes),
+	NewRule("5.1.9", SeverityMedium, "Avoid explicit endpoints under the path of a wildcard endpoint.", hasRoutesShadowedByWildcard),
+	NewRule("5.1.10", SeverityHigh, "Use the same parameter name at the same position of the paths sharing a prefix, as the router rejects the conflicting names.", hasRouteParamConflicts),
 	NewRule("5.2.1", SeverityCritical, "Ensure all endpoints have at least one backend for proper functionality.", hasEndpointWithoutBackends),
 	NewRule("5.2.2", SeverityLow, "Benefit from the backend for frontend pattern capabilities.", hasASingleBackendPerEndpoint),
 	NewRule("5.2.3", SeverityLow, "Avoid coupling clients by overusing no-op encoding.", hasAllEndpointsAsNoop),
+	NewRule("5.3.1", SeverityHigh, "Limit the max_message_size of websockets to 1MB or less.", hasUnboundedWebSocketMessages),
+	NewRule("5.3.2", SeverityMedium, "Avoid returning the error details of websockets to the clients (return_error_details).", hasWebSocketErrorDetails),
+	NewRule("5.3.3", SeverityHigh, "Set a websocket ping_period lower than the pong_wait to keep healthy connections open.", hasWebSocketPingAfterPong),
+	NewRule("5.3.4", SeverityMedium, "Reduce the websocket buffers, as each connection can retain more than 16MB.", hasLargeWebSocketBuffers),
+	NewRule("5.3.5", SeverityLow, "Limit the reconnection retries of websockets (max_retries).", hasUnlimitedWebSocketRetries),
+	NewRule("5.3.6", SeverityHigh, "Authenticate the clients of websocket endpoints.", hasUnauthenticatedWebSocket),
+	NewRule("5.3.7", SeverityMedium, "Rate limit the websocket endpoints.", hasUnlimitedWebSocket),
+	NewRule("5.4.1", SeverityHigh, "Avoid opening the Lua libraries (allow_open_libs), as they give scripts access to the filesystem and the OS.", hasLuaOpenLibs),
+	NewRule("5.4.2", SeverityMedium, "Avoid reloading the Lua sources on every request (live) in production.", hasLuaLive),
+	NewRule("5.4.3", SeverityLow, "Avoid running Lua at the router, proxy and backend layers of the same endpoint.", hasLuaInAllLayers),
+	NewRule("5.4.4", SeverityLow, "Move large inline Lua code to source files.", hasLargeInlineLua),
+	NewRule("5.5.1", SeverityLow, "Declare the content_type of the custom error body of the response schema validation.", hasResponseSchemaErrorWithoutContentType),
+	NewRule("5.5.2", SeverityMedium, "Use a 4xx or 5xx status code for the response schema validation errors.", hasResponseSchemaNonErrorStatus),
+	NewRule("5.5.3", SeverityLow, "Reduce the response schemas larger than 10KB, as they are evaluated on every response.", hasLargeResponseSchema),
+	NewRule("5.5.4", SeverityMedium, "Avoid validating the responses with both the deprecated response-schema-validator plugin and validation/response-json-schema.", hasDuplicatedResponseSchemaValidation),
+	NewRule("5.6.1", SeverityMedium, "Reduce the number of regexp modifiers of the response body, as they are evaluated on every response.", hasManyRegexpModifiers),
+	NewRule("5.6.2", SeverityMedium, "Avoid response body modifiers on no-op endpoints, as they cannot manipulate the response.", hasModifiersOnNoopEndpoint),
+	NewRule("5.6.3", SeverityLow, "Shorten the chains of response body modifiers on endpoints with multiple backends.", hasLongModifierChainOnFanOut),
 
 	/*
 	   Section 6: Async agents.
@@ -143,24 +231,33 @@ var ruleSet = []Rule{
 	   Section 7: Deprecations
 	*/
 	// 7.1 Plugin Deprecations:
-	NewRule("7.1.1", SeverityHigh, "Avoid using deprecated plugin virtualhost. Please visit https://www.krakend.io/docs/enterprise/service-settings/virtual-hosts/#upgrading-from-the-old-plugin-before-v24 to upgrade to the new virtualhost.", hasDeprecatedServerPlugin("virtualhost")),
-	NewRule("7.1.2", SeverityHigh, "Avoid using deprecated plugin static-filesystem. Please visit https://www.krakend.io/docs/enterprise/endpoints/serve-static-content/#upgrading-from-the-old-plugin-before-v24 to upgrade to the new static-filesystem.", hasDeprecatedServerPlugin("static-filesystem")),
-	NewRule("7.1.3", SeverityHigh, "Avoid using deprecated plugin basic-auth. Please move your configuration to the namespace auth/basic to use the new component. See: https://www.krakend.io/docs/enterprise/authentication/basic-authentication/ .", hasDeprecatedServerPlugin("basic-auth")),
-	NewRule("7.1.4", SeverityHigh, "Avoid using deprecated plugin wildcard. Please visit https://www.krakend.io/docs/enterprise/endpoints/wildcard/#upgrading-from-the-old-wildcard-plugin-before-v23 to upgrade to the new Wildcard.", hasDeprecatedServerPlugin("wildcard")),
+	NewRule("7.1.1", SeverityHigh, "Avoid using deprecated plugin virtualhost and upgrade to the new virtualhost.", hasDeprecatedServerPlugin("virtualhost")),
+	NewRule("7.1.2", SeverityHigh, "Avoid using deprecated plugin static-filesystem and upgrade to the new static-filesystem.", hasDeprecatedServerPlugin("static-filesystem")),
+	NewRule("7.1.3", SeverityHigh, "Avoid using deprecated plugin basic-auth and move its configuration to the auth/basic namespace.", hasDeprecatedServerPlugin("basic-auth")),
+	NewRule("7.1.4", SeverityHigh, "Avoid using deprecated plugin wildcard and upgrade to the new wildcard.", hasDeprecatedServerPlugin("wildcard")),
 
-	NewRule("7.1.5", SeverityHigh, "Avoid using deprecated plugin http-proxy. Please visit https://www.krakend.io/docs/enterprise/backends/http-proxy/#migration-from-old-plugin to upgrade to the new options.", hasDeprecatedClientPlugin("http-proxy")),
-	NewRule("7.1.6", SeverityHigh, "Avoid using deprecated plugin static-filesystem. Please visit https://www.krakend.io/docs/enterprise/endpoints/serve-static-content/#upgrading-from-the-old-plugin-before-v24 to upgrade to the new static-filesystem.", hasDeprecatedClientPlugin("static-filesystem")),
-	NewRule("7.1.7", SeverityHigh, "Avoid using deprecated plugin no-redirect. Please visit https://www.krakend.io/docs/enterprise/backends/client-redirect/#migration-from-old-plugin to upgrade to the new options.", hasDeprecatedClientPlugin("no-redirect")),
+	NewRule("7.1.5", SeverityHigh, "Avoid using deprecated plugin http-proxy and upgrade to the new options.", hasDeprecatedClientPlugin("http-proxy")),
+	NewRule("7.1.6", SeverityHigh, "Avoid using deprecated plugin static-filesystem and upgrade to the new static-filesystem.", hasDeprecatedClientPlugin("static-filesystem")),
+	NewRule("7.1.7", SeverityHigh, "Avoid using deprecated plugin no-redirect and upgrade to the new options.", hasDeprecatedClientPlugin("no-redirect")),
 
-	NewRule("7.1.8", SeverityHigh, "Avoid using deprecated plugin content-replacer. Please visit https://www.krakend.io/docs/enterprise/endpoints/content-replacer/#migration-from-old-plugin to upgrade to the new options.", hasDeprecatedReqRespPlugin("content-replacer")),
-	NewRule("7.1.9", SeverityHigh, "Avoid using deprecated plugin response-schema-validator. Please visit https://www.krakend.io/docs/enterprise/endpoints/response-schema-validator/#migration-from-old-plugin to upgrade to the new options.", hasDeprecatedReqRespPlugin("response-schema-validator")),
+	NewRule("7.1.8", SeverityHigh, "Avoid using deprecated plugin content-replacer and upgrade to the new options.", hasDeprecatedReqRespPlugin("content-replacer")),
+	NewRule("7.1.9", SeverityHigh, "Avoid using deprecated plugin response-schema-validator and upgrade to the new options.", hasDeprecatedReqRespPlugin("response-schema-validator")),
 
 	// 7.2 Component Deprecations
-	NewRule("7.2.1", SeverityHigh, "Avoid using deprecated component telemetry/ganalytics. Please visit https://www.krakend.io/docs/telemetry/opentelemetry/ to upgrade to OpenTelemetry", hasDeprecatedGanalytics),
-	NewRule("7.2.2", SeverityHigh, "Avoid using deprecated component telemetry/instana. Please visit https://www.krakend.io/docs/telemetry/opentelemetry/ to upgrade to OpenTelemetry", hasDeprecatedInstana),
-	NewRule("7.2.3", SeverityHigh, "Avoid using deprecated component telemetry/opencensus. Please visit https://www.krakend.io/docs/telemetry/opencensus/#transition-from-opencensus to upgrade to OpenTelemetry", hasDeprecatedOpenCensus),
-	NewRule("7.2.4", SeverityHigh, "Avoid using deprecated component telemetry/influx. Please visit https://www.krakend.io/docs/telemetry/influxdb/ to upgrade to OpenTelemetry", hasDeprecatedInflux),
+	NewRule("7.2.1", SeverityHigh, "Avoid using deprecated component telemetry/ganalytics and upgrade to OpenTelemetry.", hasDeprecatedGanalytics),
+	NewRule("7.2.2", SeverityHigh, "Avoid using deprecated component telemetry/instana and upgrade to OpenTelemetry.", hasDeprecatedInstana),
+	NewRule("7.2.3", SeverityHigh, "Avoid using deprecated component telemetry/opencensus and upgrade to OpenTelemetry.", hasDeprecatedOpenCensus),
+	NewRule("7.2.4", SeverityHigh, "Avoid using deprecated component telemetry/influx and upgrade to OpenTelemetry.", hasDeprecatedInflux),
 
 	// 7.3 Config field deprectaions
 	NewRule("7.3.1", SeverityMedium, "Avoid using 'private_key' and 'public_key' and use the 'keys' array.", hasDeprecatedTLSPrivPubKey),
+
+	/*
+	   Section 8: AI gateway
+	*/
+	NewRule("8.1.1", SeverityHigh, "Authenticate the clients of the LLM and MCP endpoints.", hasUnauthenticatedAIEndpoint),
+	NewRule("8.1.2", SeverityHigh, "Rate limit or set a quota on the LLM and MCP endpoints to control their cost.", hasUnlimitedAIEndpoint),
+	NewRule("8.1.3", SeverityMedium, "Set timeouts to below 2 minutes in the LLM and MCP endpoints.", hasLongAITimeout),
+	NewRule("8.1.4", SeverityLow, "Move the LLM providers to the API versions supported by the gateway.", hasOutdatedLLMVersion),
+	NewRule("8.2.1", SeverityMedium, "Split MCP servers exposing more than 20 tools.", hasLargeMCPServer),
 }
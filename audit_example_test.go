@@ -15,7 +15,7 @@ func ExampleAudit() {
 	cfg.Normalize()
 
 	exclude := []string{"1.1.1", "1.1.2", "7.2.4"}
-	levels := []string{SeverityCritical, SeverityHigh, SeverityMedium}
+	levels := []string{string(SeverityCritical), string(SeverityHigh), string(SeverityMedium)}
 
 	result, err := Audit(&cfg, exclude, levels)
 	if err != nil {
@@ -37,16 +37,24 @@ func ExampleAudit() {
 	// 06: 2.2.4 HIGH  	Avoid passing all input query strings to the backend.
 	// 07: 2.3.1 MEDIUM  	Limit the amount of cacheable content.
 	// 08: 3.1.3 HIGH  	Protect your backends with a circuit breaker.
-	// 09: 3.3.2 MEDIUM  	Set timeouts to below 5 seconds for improved performance.
-	// 10: 3.3.3 HIGH  	Set timeouts to below 30 seconds for improved performance.
-	// 11: 3.3.4 CRITICAL  	Set timeouts to below 1 minute for improved performance.
-	// 12: 4.1.1 MEDIUM  	Implement a telemetry system for collecting metrics for monitoring and troubleshooting.
-	// 13: 4.1.3 HIGH  	Avoid duplicating telemetry options to prevent system overload.
-	// 14: 4.3.1 MEDIUM  	Use the improved logging component for better log parsing.
-	// 15: 5.1.5 MEDIUM  	Declare explicit endpoints instead of using /__catchall.
-	// 16: 5.1.6 MEDIUM  	Avoid using multiple write methods in endpoint definitions.
-	// 17: 5.1.7 MEDIUM  	Avoid using sequential proxy.
-	// 18: 7.1.3 HIGH  	Avoid using deprecated plugin basic-auth. Please move your configuration to the namespace auth/basic to use the new component. See: https://www.krakend.io/docs/enterprise/authentication/basic-authentication/ .
-	// 19: 7.1.7 HIGH  	Avoid using deprecated plugin no-redirect. Please visit https://www.krakend.io/docs/enterprise/backends/client-redirect/#migration-from-old-plugin to upgrade to the new options.
-	// 20: 7.3.1 MEDIUM  	Avoid using 'private_key' and 'public_key' and use the 'keys' array.
+	// 09: 3.1.4 MEDIUM  	Rate limit every endpoint with methods that change data.
+	// 10: 3.3.2 MEDIUM  	Set timeouts to below 5 seconds for improved performance.
+	// 11: 3.3.3 HIGH  	Set timeouts to below 30 seconds for improved performance.
+	// 12: 3.3.4 CRITICAL  	Set timeouts to below 1 minute for improved performance.
+	// 13: 3.3.5 MEDIUM  	Set a read_header_timeout to close the connections of slow clients.
+	// 14: 3.3.7 MEDIUM  	Set an idle_timeout to close the idle keep-alive connections.
+	// 15: 4.1.3 HIGH  	Avoid duplicating telemetry options to prevent system overload.
+	// 16: 4.2.2 MEDIUM  	Avoid sampling all the traces in production (trace_sample_rate).
+	// 17: 4.3.1 MEDIUM  	Use the improved logging component for better log parsing.
+	// 18: 4.4.1 MEDIUM  	Send the OpenTelemetry data to remote collectors over encrypted connections.
+	// 19: 5.1.5 MEDIUM  	Declare explicit endpoints instead of using /__catchall.
+	// 20: 5.1.6 MEDIUM  	Avoid using multiple write methods in endpoint definitions.
+	// 21: 5.1.7 MEDIUM  	Avoid using sequential proxy.
+	// 22: 5.3.1 HIGH  	Limit the max_message_size of websockets to 1MB or less.
+	// 23: 5.3.4 MEDIUM  	Reduce the websocket buffers, as each connection can retain more than 16MB.
+	// 24: 5.3.6 HIGH  	Authenticate the clients of websocket endpoints.
+	// 25: 7.1.3 HIGH  	Avoid using deprecated plugin basic-auth and move its configuration to the auth/basic namespace.
+	// 26: 7.1.7 HIGH  	Avoid using deprecated plugin no-redirect and upgrade to the new options.
+	// 27: 7.3.1 MEDIUM  	Avoid using 'private_key' and 'public_key' and use the 'keys' array.
+	// 28: 8.1.1 HIGH  	Authenticate the clients of the LLM and MCP endpoints.
 }
@@ -1,6 +1,7 @@
 package audit
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/luraproject/lura/v2/config"
@@ -20,17 +21,23 @@ func TestAudit_all(t *testing.T) {
 			"2.2.3",
 			"2.2.4",
 			"2.3.1",
+			"2.5.4", // -- backends with a single static host
 			"3.1.1",
 			// "3.1.2", -- we added service level rate limit
 			"3.1.3",
+			"3.1.4", // -- post and delete endpoints without rate limit
 			"3.3.1",
 			"3.3.2",
 			"3.3.3",
 			"3.3.4",
-			"4.1.1",
+			"3.3.5", // -- no read_header_timeout
+			"3.3.7", // -- no idle_timeout nor read timeouts
+			// "4.1.1", -- opentelemetry is enabled for metrics
 			"4.1.3", // -- we have prometheus and otel metrics
 			// "4.2.1", -- opentelemetryis enabled for tracing
+			"4.2.2", // -- all the traces are sampled
 			"4.3.1",
+			"4.4.1", // -- otlp exporters without a scheme default to http
 			"5.1.1",
 			"5.1.2",
 			"5.1.3",
@@ -38,13 +45,19 @@ func TestAudit_all(t *testing.T) {
 			"5.1.5",
 			"5.1.6",
 			"5.1.7",
+			"5.3.1", // -- websocket max_message_size above 1MB
+			"5.3.4", // -- websocket message buffer of 4096 messages
+			"5.3.5", // -- websocket with unlimited retries
+			"5.3.6", // -- websocket without authentication
+			"5.4.3", // -- lua at the router, proxy and backend layers
 			// "5.2.2", -- we added multiple backends to the test to check for multiple unsafe methods
 			"7.1.3", // deprecated server plugin basic auth
 			"7.1.7", // deprecated client plugin no-redirect
 			"7.2.4", // deprecated influx
 			"7.3.1", // deprecated TLS private_key and public_key
+			"8.1.1", // -- llm and mcp endpoints without authentication
 		},
-		levels: []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow},
+		levels: []string{string(SeverityCritical), string(SeverityHigh), string(SeverityMedium), string(SeverityLow)},
 	}
 	testAudit(t, tc)
 }
@@ -61,17 +74,23 @@ func TestAudit_exclude(t *testing.T) {
 			"2.2.3",
 			"2.2.4",
 			"2.3.1",
+			"2.5.4", // -- backends with a single static host
 			"3.1.1",
 			// "3.1.2", -- add added service level rate limit
 			"3.1.3",
+			"3.1.4", // -- post and delete endpoints without rate limit
 			"3.3.1",
 			"3.3.2",
 			"3.3.3",
 			"3.3.4",
-			"4.1.1",
+			"3.3.5", // -- no read_header_timeout
+			"3.3.7", // -- no idle_timeout nor read timeouts
+			// "4.1.1", -- opentelemetry is enabled for metrics
 			"4.1.3", // -- we have prometheus and otel metrics
 			// "4.2.1", -- opentelemetry is enabled for tracing
+			"4.2.2", // -- all the traces are sampled
 			"4.3.1",
+			"4.4.1", // -- otlp exporters without a scheme default to http
 			"5.1.1",
 			"5.1.2",
 			"5.1.3",
@@ -79,13 +98,19 @@ func TestAudit_exclude(t *testing.T) {
 			"5.1.5",
 			"5.1.6",
 			"5.1.7",
+			"5.3.1", // -- websocket max_message_size above 1MB
+			"5.3.4", // -- websocket message buffer of 4096 messages
+			"5.3.5", // -- websocket with unlimited retries
+			"5.3.6", // -- websocket without authentication
+			"5.4.3", // -- lua at the router, proxy and backend layers
 			// "5.2.2", -- we added multiple backends to the test to check for multiple unsafe methods
 			"7.1.3", // deprecated plugin basic-auth
 			"7.1.7", // deprecated client plugin no-redirect
 			"7.3.1", // deprecated TLS private_key and public_key
+			"8.1.1", // -- llm and mcp endpoints without authentication
 		},
 		exclude: []string{"1.1.1", "1.1.2", "7.2.4"},
-		levels:  []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow},
+		levels:  []string{string(SeverityCritical), string(SeverityHigh), string(SeverityMedium), string(SeverityLow)},
 	}
 	testAudit(t, tc)
 }
@@ -96,7 +121,7 @@ func TestAudit_severity(t *testing.T) {
 			"2.1.3",
 			"3.3.4",
 		},
-		levels: []string{SeverityCritical},
+		levels: []string{string(SeverityCritical)},
 	}
 	testAudit(t, tc)
 }
@@ -135,3 +160,82 @@ func testAudit(t *testing.T, tc testCase) {
 		}
 	}
 }
+
+func TestAudit_locations(t *testing.T) {
+	cfg, err := config.NewParser().Parse("./tests/example1.json")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	cfg.Normalize()
+
+	result, err := Audit(&cfg, []string{}, []string{string(SeverityCritical), string(SeverityHigh), string(SeverityMedium), string(SeverityLow)})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := map[string][]Location{
+		"2.1.3": {{Pointer: "/tls/disabled"}},
+		"2.1.9": {{Pointer: "/endpoints/1/backend/0/extra_config/backend~1http~1client", Endpoint: "/wildcarded/resource/*", Method: "GET"}},
+		"2.2.3": {{Pointer: "/endpoints/1/input_headers", Endpoint: "/wildcarded/resource/*", Method: "GET"}},
+		"3.3.3": {{Pointer: "/endpoints/0/timeout", Endpoint: "/protected/resource", Method: "GET"}},
+		"5.1.4": {{Pointer: "/endpoints/1/endpoint", Endpoint: "/wildcarded/resource/*", Method: "GET"}},
+		"5.1.5": {{Pointer: "/endpoints/12/endpoint", Endpoint: "/__catchall", Method: "GET"}},
+	}
+
+	for _, r := range result.Recommendations {
+		locations, ok := expected[r.Rule]
+		if !ok {
+			continue
+		}
+		delete(expected, r.Rule)
+		if !reflect.DeepEqual(r.Locations, locations) {
+			t.Errorf("unexpected locations for rule %s. have: %+v, want: %+v", r.Rule, r.Locations, locations)
+		}
+	}
+	for id := range expected {
+		t.Errorf("missing recommendation: %s", id)
+	}
+}
+
+func TestAudit_unknownSeverity(t *testing.T) {
+	cfg, err := config.NewParser().Parse("./tests/example1.json")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	cfg.Normalize()
+
+	result, err := Audit(&cfg, []string{}, []string{string(SeverityHigh), "CRTICAL", "critical"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(result.Recommendations) == 0 {
+		t.Error("the known severities are ignored")
+	}
+	for _, r := range result.Recommendations {
+		if r.Severity != SeverityHigh {
+			t.Errorf("unexpected severity of rule %s: %s", r.Rule, r.Severity)
+		}
+	}
+}
+
+func TestAudit_ignorePatterns(t *testing.T) {
+	cfg, err := config.NewParser().Parse("./tests/example1.json")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	cfg.Normalize()
+
+	result, err := Audit(&cfg, []string{"3.*", "2.1.3"}, []string{string(SeverityCritical)})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(result.Recommendations) != 1 || result.Recommendations[0].Rule != "3.3.4" {
+		t.Errorf("unexpected recommendations: %+v", result.Recommendations)
+	}
+}
@@ -0,0 +1,279 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/luraproject/lura/v2/config"
+)
+
+var (
+	// ErrInvalidRule is returned when registering a rule without id or evaluation function
+	ErrInvalidRule = errors.New("invalid rule")
+	// ErrInvalidSeverity is returned when a severity is not one of the supported ones
+	ErrInvalidSeverity = errors.New("invalid severity")
+	// ErrDuplicatedRule is returned when registering a rule with an already registered id
+	ErrDuplicatedRule = errors.New("duplicated rule")
+	// ErrUnknownRule is returned when replacing or removing a rule that is not registered
+	ErrUnknownRule = errors.New("unknown rule")
+)
+
+// Auditor audits configurations against its own set of rules. A new Auditor starts with
+// the built-in rules, and they can be extended, replaced or removed with the options
+// passed to NewAuditor or at any moment after its creation.
+type Auditor struct {
+	mu    sync.RWMutex
+	rules []Rule
+}
+
+// AuditorOption customizes the Auditor created by NewAuditor
+type AuditorOption func(*Auditor) error
+
+// WithRules registers the received rules in the Auditor
+func WithRules(rs ...Rule) AuditorOption {
+	return func(a *Auditor) error {
+		for _, r := range rs {
+			if err := a.Register(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// WithReplacedRules replaces the registered rules with the same id as the received ones
+func WithReplacedRules(rs ...Rule) AuditorOption {
+	return func(a *Auditor) error {
+		for _, r := range rs {
+			if err := a.Replace(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// WithoutRules removes the rules with the received ids from the Auditor
+func WithoutRules(ids ...string) AuditorOption {
+	return func(a *Auditor) error {
+		for _, id := range ids {
+			if err := a.Remove(id); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// NewAuditor returns an Auditor with the built-in rules, customized by the received options
+func NewAuditor(opts ...AuditorOption) (*Auditor, error) {
+	a := &Auditor{
+		rules: make([]Rule, 0, len(ruleSet)),
+	}
+	for _, r := range builtinRules() {
+		if err := a.Register(r); err != nil {
+			return nil, err
+		}
+	}
+	for _, opt := range opts {
+		if err := opt(a); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// Register adds the rule to the Auditor. It fails if the rule is malformed or if there
+// is already a rule with the same id.
+func (a *Auditor) Register(r Rule) error {
+	if err := validateRule(r); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.indexOf(r.Recommendation.Rule) >= 0 {
+		return fmt.Errorf("%w: %s", ErrDuplicatedRule, r.Recommendation.Rule)
+	}
+	a.rules = append(a.rules, r)
+	return nil
+}
+
+// Replace swaps the registered rule having the same id with the received one
+func (a *Auditor) Replace(r Rule) error {
+	if err := validateRule(r); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	i := a.indexOf(r.Recommendation.Rule)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownRule, r.Recommendation.Rule)
+	}
+	a.rules[i] = r
+	return nil
+}
+
+// Remove deletes the rule with the received id from the Auditor
+func (a *Auditor) Remove(id string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	i := a.indexOf(id)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownRule, id)
+	}
+	a.rules = append(a.rules[:i], a.rules[i+1:]...)
+	return nil
+}
+
+// Rules returns a copy of the rules registered in the Auditor, in evaluation order
+func (a *Auditor) Rules() []Rule {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	res := make([]Rule, len(a.rules))
+	copy(res, a.rules)
+	return res
+}
+
+// Audit audits the received configuration and generates an AuditResult with all the
+// Recommendations of the registered rules, skipping the ignored rules and the ones
+// with a severity not included in the received list. The ignored ids are matched
+// exactly and the unsupported severities are ignored. Use Run with the ExcludeRules and
+// WithSeverities options for patterns and the validation of the severities
+func (a *Auditor) Audit(cfg *config.ServiceConfig, ignore, severities []string) (AuditResult, error) {
+	ss := []Severity{}
+	for _, s := range severities {
+		if s := Severity(s); s.IsValid() {
+			ss = append(ss, s)
+		}
+	}
+	return a.Run(cfg, ignoreRules(ignore...), onlySeverities(ss))
+}
+
+// Run audits the received configuration and generates an AuditResult with all the
+// Recommendations of the registered rules selected by the received options
+func (a *Auditor) Run(cfg *config.ServiceConfig, opts ...Option) (AuditResult, error) {
+	start := time.Now()
+	o, err := newOptions(opts)
+	if err != nil {
+		return AuditResult{}, err
+	}
+	if err := o.ctx.Err(); err != nil {
+		return AuditResult{}, err
+	}
+
+	rules := a.Rules()
+	for id := range o.overrides {
+		if indexOf(rules, id) < 0 {
+			return AuditResult{}, fmt.Errorf("%w: cannot override the severity of %s", ErrUnknownRule, id)
+		}
+	}
+
+	suppressions, err := parseSuppressions(cfg)
+	if err != nil {
+		return AuditResult{}, err
+	}
+	for _, s := range suppressions {
+		for _, id := range s.Rules {
+			if indexOf(rules, id) < 0 {
+				return AuditResult{}, fmt.Errorf("%w at %q: %w: %s", ErrInvalidSuppression, s.Pointer, ErrUnknownRule, id)
+			}
+		}
+	}
+	now := o.now()
+
+	service := Parse(cfg)
+
+	res := AuditResult{
+		Recommendations: []Recommendation{},
+		Suppressed:      []SuppressedRecommendation{},
+		Stats:           newStats(&service),
+	}
+	score := newScorer(o.weights, len(service.Endpoints))
+	res.Stats.Rules.Total = len(rules)
+
+	for i := range rules {
+		if err := o.ctx.Err(); err != nil {
+			return AuditResult{}, err
+		}
+
+		if !o.selects(rules[i].Recommendation.Rule) {
+			res.Stats.Rules.Ignored++
+			continue
+		}
+
+		r := rules[i].Recommendation
+		if severity, ok := o.overrides[r.Rule]; ok && severity != r.Severity {
+			r.OriginalSeverity = r.Severity
+			r.Severity = severity
+		}
+
+		if !o.catches(r.Severity) {
+			res.Stats.Rules.FilteredBySeverity++
+			continue
+		}
+
+		res.Stats.Rules.Evaluated++
+		score.evaluated(r.Rule, r.Severity)
+		scopes := rules[i].Evaluate(&service)
+		if len(scopes) == 0 {
+			continue
+		}
+		r.Locations = make([]Location, len(scopes))
+		for j, scope := range scopes {
+			r.Locations[j] = newLocation(cfg, scope)
+		}
+
+		r, suppressed := suppress(r, suppressions, now)
+		res.Suppressed = append(res.Suppressed, suppressed...)
+		res.Stats.Findings.Suppressed += len(suppressed)
+		if len(r.Locations) == 0 {
+			continue
+		}
+		res.Recommendations = append(res.Recommendations, r)
+		res.Stats.addFinding(r)
+		score.finding(r)
+	}
+	res.Score = score.score()
+
+	if o.baseline != nil {
+		diff := Compare(*o.baseline, res)
+		res.Baseline = &diff
+	}
+
+	res.Stats.Duration = time.Since(start)
+	return res, nil
+}
+
+func (a *Auditor) indexOf(id string) int {
+	return indexOf(a.rules, id)
+}
+
+func indexOf(rules []Rule, id string) int {
+	for i := range rules {
+		if rules[i].Recommendation.Rule == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func validateRule(r Rule) error {
+	if r.Recommendation.Rule == "" {
+		return fmt.Errorf("%w: missing id", ErrInvalidRule)
+	}
+	if r.Evaluate == nil {
+		return fmt.Errorf("%w: rule %s without evaluation function", ErrInvalidRule, r.Recommendation.Rule)
+	}
+	if r.Recommendation.Severity.IsValid() {
+		return nil
+	}
+	return fmt.Errorf("%w: rule %s has severity %q", ErrInvalidSeverity, r.Recommendation.Rule, r.Recommendation.Severity)
+}
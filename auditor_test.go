@@ -0,0 +1,120 @@
+package audit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/luraproject/lura/v2/config"
+)
+
+func hasNoEndpoints(s *Service) []Scope {
+	if len(s.Endpoints) == 0 {
+		return []Scope{serviceScope("endpoints")}
+	}
+	return nil
+}
+
+func hasEndpoints(s *Service) []Scope {
+	if len(s.Endpoints) > 0 {
+		return []Scope{serviceScope("endpoints")}
+	}
+	return nil
+}
+
+func TestNewAuditor(t *testing.T) {
+	a, err := NewAuditor()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(a.Rules()) != len(ruleSet) {
+		t.Errorf("unexpected number of rules. have: %d, want: %d", len(a.Rules()), len(ruleSet))
+	}
+}
+
+func TestNewAuditor_customRules(t *testing.T) {
+	a, err := NewAuditor(
+		WithoutRules("1.1.1", "1.1.2"),
+		WithRules(NewRule("99.1.1", SeverityHigh, "Declare at least one endpoint.", hasNoEndpoints)),
+		WithReplacedRules(NewRule("2.1.3", SeverityLow, "The configuration has endpoints.", hasEndpoints)),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	rules := a.Rules()
+	if len(rules) != len(ruleSet)-1 {
+		t.Errorf("unexpected number of rules. have: %d, want: %d", len(rules), len(ruleSet)-1)
+	}
+	if id := rules[len(rules)-1].Recommendation.Rule; id != "99.1.1" {
+		t.Errorf("unexpected last rule: %s", id)
+	}
+
+	cfg := &config.ServiceConfig{Endpoints: []*config.EndpointConfig{{Endpoint: "/foo", Method: "GET"}}}
+	result, err := a.Audit(cfg, nil, []string{string(SeverityLow)})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	found := false
+	for _, r := range result.Recommendations {
+		switch r.Rule {
+		case "1.1.1", "1.1.2", "99.1.1":
+			t.Errorf("unexpected recommendation %s", r.Rule)
+		case "2.1.3":
+			found = true
+			if r.Message != "The configuration has endpoints." {
+				t.Errorf("unexpected message: %s", r.Message)
+			}
+		}
+	}
+	if !found {
+		t.Error("the replaced rule has not been evaluated")
+	}
+}
+
+func TestAuditor_errors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opt  AuditorOption
+		err  error
+	}{
+		{
+			name: "duplicated",
+			opt:  WithRules(NewRule("1.1.1", SeverityHigh, "foo", hasEndpoints)),
+			err:  ErrDuplicatedRule,
+		},
+		{
+			name: "malformed severity",
+			opt:  WithRules(NewRule("99.1.1", "high", "foo", hasEndpoints)),
+			err:  ErrInvalidSeverity,
+		},
+		{
+			name: "missing id",
+			opt:  WithRules(NewRule("", SeverityHigh, "foo", hasEndpoints)),
+			err:  ErrInvalidRule,
+		},
+		{
+			name: "missing evaluation",
+			opt:  WithRules(NewRule("99.1.1", SeverityHigh, "foo", nil)),
+			err:  ErrInvalidRule,
+		},
+		{
+			name: "replace unknown",
+			opt:  WithReplacedRules(NewRule("99.1.1", SeverityHigh, "foo", hasEndpoints)),
+			err:  ErrUnknownRule,
+		},
+		{
+			name: "remove unknown",
+			opt:  WithoutRules("99.1.1"),
+			err:  ErrUnknownRule,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewAuditor(tc.opt); !errors.Is(err, tc.err) {
+				t.Errorf("unexpected error. have: %v, want: %v", err, tc.err)
+			}
+		})
+	}
+}
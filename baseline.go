@@ -0,0 +1,137 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// BaselineDiff classifies the findings of an audit against the ones of a previous audit.
+// Findings are matched by rule id and location, and the locations under endpoints and
+// async agents are identified by the method and path of the endpoint or the name of the
+// agent instead of their index, so reordering them does not change the classification
+type BaselineDiff struct {
+	New       []Recommendation `json:"new"`
+	Unchanged []Recommendation `json:"unchanged"`
+	Resolved  []Recommendation `json:"resolved"`
+}
+
+// LoadBaseline decodes a previously saved AuditResult to be used as a baseline
+func LoadBaseline(r io.Reader) (AuditResult, error) {
+	var res AuditResult
+	err := json.NewDecoder(r).Decode(&res)
+	return res, err
+}
+
+// WithBaseline compares the findings of the audit with the ones of the received result
+// and adds the classification to the Baseline section of the AuditResult
+func WithBaseline(baseline AuditResult) Option {
+	return func(o *options) {
+		o.baseline = &baseline
+	}
+}
+
+// Compare classifies the recommendations of the current result as new or unchanged, and
+// the ones of the baseline that are not present anymore as resolved. Recommendations of
+// the baseline without locations match every location of the same rule
+func Compare(baseline, current AuditResult) BaselineDiff {
+	diff := BaselineDiff{
+		New:       []Recommendation{},
+		Unchanged: []Recommendation{},
+		Resolved:  []Recommendation{},
+	}
+
+	previous := findingKeys(baseline.Recommendations)
+	actual := findingKeys(current.Recommendations)
+
+	for _, r := range current.Recommendations {
+		if _, ok := previous[findingKey{rule: r.Rule}]; ok {
+			diff.Unchanged = append(diff.Unchanged, r)
+			continue
+		}
+		newFinding, unchanged := splitLocations(r, previous)
+		if len(newFinding.Locations) > 0 || len(r.Locations) == 0 {
+			diff.New = append(diff.New, newFinding)
+		}
+		if len(unchanged.Locations) > 0 {
+			diff.Unchanged = append(diff.Unchanged, unchanged)
+		}
+	}
+
+	for _, r := range baseline.Recommendations {
+		if len(r.Locations) == 0 {
+			if !hasRule(current.Recommendations, r.Rule) {
+				diff.Resolved = append(diff.Resolved, r)
+			}
+			continue
+		}
+		resolved, _ := splitLocations(r, actual)
+		if len(resolved.Locations) > 0 {
+			diff.Resolved = append(diff.Resolved, resolved)
+		}
+	}
+
+	return diff
+}
+
+type findingKey struct {
+	rule     string
+	location string
+}
+
+func findingKeys(rs []Recommendation) map[findingKey]struct{} {
+	res := map[findingKey]struct{}{}
+	for _, r := range rs {
+		if len(r.Locations) == 0 {
+			res[findingKey{rule: r.Rule}] = struct{}{}
+			continue
+		}
+		for _, l := range r.Locations {
+			res[findingKey{rule: r.Rule, location: locationKey(l)}] = struct{}{}
+		}
+	}
+	return res
+}
+
+// splitLocations returns a copy of the recommendation with the locations not present in the
+// received set and another one with the locations present in it
+func splitLocations(r Recommendation, keys map[findingKey]struct{}) (Recommendation, Recommendation) {
+	missing, present := r, r
+	missing.Locations, present.Locations = nil, nil
+	for _, l := range r.Locations {
+		if _, ok := keys[findingKey{rule: r.Rule, location: locationKey(l)}]; ok {
+			present.Locations = append(present.Locations, l)
+			continue
+		}
+		missing.Locations = append(missing.Locations, l)
+	}
+	return missing, present
+}
+
+func hasRule(rs []Recommendation, id string) bool {
+	for _, r := range rs {
+		if r.Rule == id {
+			return true
+		}
+	}
+	return false
+}
+
+// locationKey identifies a location without depending on the index of its endpoint or agent
+func locationKey(l Location) string {
+	var prefix string
+	switch {
+	case l.Endpoint != "":
+		prefix = l.Method + " " + l.Endpoint
+	case l.Agent != "":
+		prefix = "agent " + l.Agent
+	default:
+		return l.Pointer
+	}
+	// drop the "/endpoints/<index>" or "/async_agent/<index>" tokens
+	parts := strings.SplitN(l.Pointer, "/", 4)
+	if len(parts) < 4 {
+		return prefix
+	}
+	return prefix + " /" + parts[3]
+}
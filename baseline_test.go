@@ -0,0 +1,85 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/luraproject/lura/v2/config"
+)
+
+func TestRun_baseline(t *testing.T) {
+	previous, err := Run(loadExample1(t), WithSections("2", "5"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	b, err := json.Marshal(previous)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	baseline, err := LoadBaseline(bytes.NewBuffer(b))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	cfg := loadExample1(t)
+	// moving the endpoints around should not change the classification of their findings
+	cfg.Endpoints[0], cfg.Endpoints[1] = cfg.Endpoints[1], cfg.Endpoints[0]
+	// resolve the query string wildcard
+	cfg.Endpoints[0].QueryString = []string{"page"}
+	// and pass all the headers in a new endpoint
+	cfg.Endpoints = append(cfg.Endpoints, &config.EndpointConfig{
+		Endpoint:      "/new",
+		Method:        "GET",
+		HeadersToPass: []string{"*"},
+		Backend:       []*config.Backend{{URLPattern: "/"}},
+	})
+
+	result, err := Run(cfg, WithSections("2", "5"), WithBaseline(baseline))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if result.Baseline == nil {
+		t.Error("the baseline comparison is missing")
+		return
+	}
+	diff := result.Baseline
+
+	if len(diff.New) != 1 || diff.New[0].Rule != "2.2.3" || len(diff.New[0].Locations) != 1 || diff.New[0].Locations[0].Endpoint != "/new" {
+		t.Errorf("unexpected new findings: %+v", diff.New)
+	}
+	if len(diff.Resolved) != 1 || diff.Resolved[0].Rule != "2.2.4" || diff.Resolved[0].Locations[0].Pointer != "/endpoints/1/input_query_strings" {
+		t.Errorf("unexpected resolved findings: %+v", diff.Resolved)
+	}
+	if len(diff.Unchanged) != len(result.Recommendations) {
+		t.Errorf("unexpected number of unchanged findings. have: %d, want: %d", len(diff.Unchanged), len(result.Recommendations))
+	}
+	for _, r := range diff.Unchanged {
+		if r.Rule == "2.2.3" && (len(r.Locations) != 1 || r.Locations[0].Pointer != "/endpoints/0/input_headers") {
+			t.Errorf("unexpected unchanged locations for 2.2.3: %+v", r.Locations)
+		}
+	}
+}
+
+func TestCompare_withoutLocations(t *testing.T) {
+	baseline := AuditResult{Recommendations: []Recommendation{{Rule: "2.2.3"}, {Rule: "2.2.4"}}}
+	current := AuditResult{Recommendations: []Recommendation{
+		{Rule: "2.2.3", Locations: []Location{{Pointer: "/endpoints/0/input_headers", Endpoint: "/foo", Method: "GET"}}},
+		{Rule: "5.1.4", Locations: []Location{{Pointer: "/endpoints/0/endpoint", Endpoint: "/foo", Method: "GET"}}},
+	}}
+
+	diff := Compare(baseline, current)
+	if len(diff.New) != 1 || diff.New[0].Rule != "5.1.4" {
+		t.Errorf("unexpected new findings: %+v", diff.New)
+	}
+	if len(diff.Unchanged) != 1 || diff.Unchanged[0].Rule != "2.2.3" {
+		t.Errorf("unexpected unchanged findings: %+v", diff.Unchanged)
+	}
+	if len(diff.Resolved) != 1 || diff.Resolved[0].Rule != "2.2.4" {
+		t.Errorf("unexpected resolved findings: %+v", diff.Resolved)
+	}
+}
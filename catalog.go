@@ -0,0 +1,1030 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// RuleMetadata documents a rule so its recommendations can be explained and acted upon.
+// Enterprise is set when the rule only applies to KrakenD Enterprise configurations
+type RuleMetadata struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Rationale   string   `json:"rationale"`
+	Remediation []string `json:"remediation"`
+	DocURL      string   `json:"doc_url,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Enterprise  bool     `json:"enterprise"`
+}
+
+// RuleInfo describes a registered rule: its recommendation, the section of the rule set
+// it belongs to and its metadata
+type RuleInfo struct {
+	ID       string   `json:"id"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Section  string   `json:"section"`
+	Category string   `json:"category"`
+	RuleMetadata
+}
+
+// SectionName returns the name of the section of the rule set, or an empty string for
+// unknown sections
+func SectionName(section string) string {
+	return sectionNames[section]
+}
+
+var sectionNames = map[string]string{
+	"1": "Security",
+	"2": "Service",
+	"3": "Traffic management",
+	"4": "Telemetry",
+	"5": "Endpoints",
+	"6": "Async agents",
+	"7": "Deprecations",
+	"8": "AI gateway",
+}
+
+// ListRules returns the catalog of the built-in rules
+func ListRules() []RuleInfo {
+	a, err := NewAuditor()
+	if err != nil {
+		return []RuleInfo{}
+	}
+	return a.ListRules()
+}
+
+// WriteCatalog encodes the catalog of the built-in rules as JSON into the received writer
+func WriteCatalog(w io.Writer) error {
+	a, err := NewAuditor()
+	if err != nil {
+		return err
+	}
+	return a.WriteCatalog(w)
+}
+
+// ListRules returns the catalog of the rules registered in the Auditor, in evaluation order
+func (a *Auditor) ListRules() []RuleInfo {
+	rules := a.Rules()
+	res := make([]RuleInfo, len(rules))
+	for i, r := range rules {
+		section := ruleSection(r.Recommendation.Rule)
+		res[i] = RuleInfo{
+			ID:           r.Recommendation.Rule,
+			Severity:     r.Recommendation.Severity,
+			Message:      r.Recommendation.Message,
+			Section:      section,
+			Category:     SectionName(section),
+			RuleMetadata: r.Metadata,
+		}
+	}
+	return res
+}
+
+// WriteCatalog encodes the catalog of the rules registered in the Auditor as JSON into
+// the received writer
+func (a *Auditor) WriteCatalog(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(a.ListRules())
+}
+
+// builtinRules returns a copy of the built-in rule set with the metadata of each rule
+func builtinRules() []Rule {
+	res := make([]Rule, len(ruleSet))
+	for i, r := range ruleSet {
+		r.Metadata = ruleMetadata[r.Recommendation.Rule]
+		res[i] = r
+	}
+	return res
+}
+
+const (
+	tagOWASPAuthentication     = "OWASP-API2:2023"
+	tagOWASPPropertyLevelAuthz = "OWASP-API3:2023"
+	tagOWASPResourceLimits     = "OWASP-API4:2023"
+	tagOWASPMisconfiguration   = "OWASP-API8:2023"
+	tagOWASPInventory          = "OWASP-API9:2023"
+	tagOWASPUnsafeConsumption  = "OWASP-API10:2023"
+)
+
+var ruleMetadata = map[string]RuleMetadata{
+	"1.1.1": {
+		Title:       "Basic Authentication in use",
+		Description: "The configuration protects the service or some endpoints with Basic Authentication.",
+		Rationale:   "Basic Authentication sends reusable credentials on every request and offers no expiration, scopes or revocation.",
+		Remediation: []string{"Replace Basic Authentication with token based authorization such as JWT validation.", "If it must stay, restrict it to internal endpoints served over TLS."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/authentication/basic-authentication/",
+		Tags:        []string{"CWE-522", tagOWASPAuthentication},
+		Enterprise:  true,
+	},
+	"1.1.2": {
+		Title:       "API keys in use",
+		Description: "The configuration authorizes requests with API keys.",
+		Rationale:   "API keys are long-lived shared secrets that are hard to rotate and carry no claims about the caller.",
+		Remediation: []string{"Prefer stateless, short-lived tokens such as JWT for end users.", "Keep API keys for machine to machine traffic and rotate them periodically."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/authentication/api-keys/",
+		Tags:        []string{"CWE-798", tagOWASPAuthentication},
+		Enterprise:  true,
+	},
+	"1.2.1": {
+		Title:       "No JWT validation",
+		Description: "None of the endpoints validates JSON Web Tokens.",
+		Rationale:   "Without token validation at the gateway, every backend must implement its own authorization and any mistake exposes data.",
+		Remediation: []string{"Add the auth/validator namespace to the endpoints that require authorization."},
+		DocURL:      "https://www.krakend.io/docs/authorization/jwt-validation/",
+		Tags:        []string{"CWE-306", tagOWASPAuthentication},
+	},
+	"1.2.2": {
+		Title:       "No token revocation",
+		Description: "Some endpoints validate JWT but the service has no mechanism to revoke them.",
+		Rationale:   "A stolen or leaked token stays valid until it expires, which is a long window for long-lived tokens.",
+		Remediation: []string{"Add the github_com/devopsfaith/bloomfilter namespace with the token_keys to revoke.", "Keep the lifetime of the tokens short if revocation is not an option."},
+		DocURL:      "https://www.krakend.io/docs/authorization/revoking-tokens/",
+		Tags:        []string{"CWE-613", tagOWASPAuthentication},
+	},
+	"1.2.3": {
+		Title:       "Revocation without token keys",
+		Description: "The revocation bloomfilter is enabled but declares no token_keys.",
+		Rationale:   "The bloomfilter only checks the claims listed in token_keys, so without them no token is ever considered revoked.",
+		Remediation: []string{"Add the claims that identify the tokens to revoke, such as jti or sub, to token_keys."},
+		DocURL:      "https://www.krakend.io/docs/authorization/revoking-tokens/",
+		Tags:        []string{"CWE-613", tagOWASPAuthentication},
+	},
+	"1.2.4": {
+		Title:       "Revocation without JWT validation",
+		Description: "The revocation bloomfilter is enabled but no endpoint validates JWT.",
+		Rationale:   "The bloomfilter is only consulted by the JWT validator, so it consumes memory without protecting anything.",
+		Remediation: []string{"Add the auth/validator namespace to the protected endpoints, or remove the bloomfilter."},
+		DocURL:      "https://www.krakend.io/docs/authorization/revoking-tokens/",
+		Tags:        []string{tagOWASPMisconfiguration},
+	},
+	"1.2.5": {
+		Title:       "Non-optimal bloomfilter hashing",
+		Description: "The revocation bloomfilter does not use the optimal hash function.",
+		Rationale:   "The default hash function produces more false positives, rejecting valid tokens, for the same memory size.",
+		Remediation: []string{"Set hash_name to optimal in the bloomfilter configuration."},
+		DocURL:      "https://www.krakend.io/docs/authorization/revoking-tokens/",
+	},
+	"2.1.1": {
+		Title:       "Insecure connections allowed",
+		Description: "The service accepts invalid or self-signed certificates when connecting to the backends.",
+		Rationale:   "Skipping the certificate validation enables man-in-the-middle attacks against the upstream traffic.",
+		Remediation: []string{"Remove allow_insecure_connections from the service and from client_tls.", "Add the private CA to ca_certs instead of disabling the validation."},
+		DocURL:      "https://www.krakend.io/docs/service-settings/tls/",
+		Tags:        []string{"CWE-295", tagOWASPUnsafeConsumption},
+	},
+	"2.1.2": {
+		Title:       "TLS not configured",
+		Description: "The service does not terminate TLS.",
+		Rationale:   "Unless a terminator sits in front of the gateway, credentials and data travel in clear text.",
+		Remediation: []string{"Add a tls section with the certificates of the service.", "Or make sure a TLS terminator is the only entry point to the gateway."},
+		DocURL:      "https://www.krakend.io/docs/service-settings/tls/",
+		Tags:        []string{"CWE-319", tagOWASPMisconfiguration},
+	},
+	"2.1.3": {
+		Title:       "TLS disabled",
+		Description: "The tls section is present but its disabled flag is set.",
+		Rationale:   "The configuration looks protected while the service actually listens in clear text.",
+		Remediation: []string{"Remove the disabled flag from the tls section, or remove the section if a terminator handles TLS."},
+		DocURL:      "https://www.krakend.io/docs/service-settings/tls/",
+		Tags:        []string{"CWE-319", tagOWASPMisconfiguration},
+	},
+	"2.1.4": {
+		Title:       "Outdated TLS version allowed",
+		Description: "The server, the http client or some backends accept TLS versions below 1.2.",
+		Rationale:   "SSL 3.0, TLS 1.0 and TLS 1.1 are deprecated (RFC 8996) and vulnerable to known downgrade and padding attacks.",
+		Remediation: []string{"Set min_version to TLS12 or TLS13, or remove it to use the TLS 1.3 default."},
+		DocURL:      "https://www.krakend.io/docs/service-settings/tls/",
+		Tags:        []string{"CWE-326", tagOWASPMisconfiguration},
+	},
+	"2.1.5": {
+		Title:       "Insecure cipher suites",
+		Description: "The declared cipher suites include suites with known weaknesses or not supported by the Go runtime.",
+		Rationale:   "Suites based on RC4 or 3DES, or with known implementation weaknesses, do not protect the confidentiality of the traffic against a capable attacker.",
+		Remediation: []string{"Declare only ECDHE suites with AES-GCM or ChaCha20-Poly1305, or remove cipher_suites to use the defaults.", "Set min_version to TLS13, where the suites are not configurable."},
+		DocURL:      "https://www.krakend.io/docs/service-settings/tls/",
+		Tags:        []string{"CWE-327", tagOWASPMisconfiguration},
+	},
+	"2.1.6": {
+		Title:       "Only CBC cipher suites",
+		Description: "All the declared cipher suites use CBC mode encryption.",
+		Rationale:   "CBC suites have a long history of padding oracle and timing attacks, and AEAD suites are supported by every modern client.",
+		Remediation: []string{"Add AEAD suites such as TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256 to cipher_suites, or remove the list to use the defaults."},
+		DocURL:      "https://www.krakend.io/docs/service-settings/tls/",
+		Tags:        []string{"CWE-327", tagOWASPMisconfiguration},
+	},
+	"2.1.7": {
+		Title:       "HTTP security headers not enforced",
+		Description: "The security/http component is not enabled.",
+		Rationale:   "Security headers such as HSTS, frame and content type options protect clients from common browser attacks.",
+		Remediation: []string{"Add the security/http namespace to the service extra_config with the headers required by your clients."},
+		DocURL:      "https://www.krakend.io/docs/service-settings/security/",
+		Tags:        []string{"CWE-693", tagOWASPMisconfiguration},
+	},
+	"2.1.8": {
+		Title:       "Clear text HTTP/2 enabled",
+		Description: "The service accepts HTTP/2 without TLS (h2c).",
+		Rationale:   "h2c traffic is not encrypted and should only be used behind a trusted terminator.",
+		Remediation: []string{"Remove use_h2c unless the gateway only receives traffic from a trusted load balancer."},
+		Tags:        []string{"CWE-319", tagOWASPMisconfiguration},
+	},
+	"2.1.9": {
+		Title:       "Insecure connections to backends",
+		Description: "Some backends skip the validation of the certificates of the upstream services.",
+		Rationale:   "Internal networks are not automatically trusted networks, and skipping the validation enables traffic interception.",
+		Remediation: []string{"Remove allow_insecure_connections from the client_tls of the backend/http/client namespace.", "Add the internal CA to the ca_certs of the client instead."},
+		Tags:        []string{"CWE-295", tagOWASPUnsafeConsumption},
+	},
+	"2.1.10": {
+		Title:       "mTLS trusts the system CA pool",
+		Description: "Mutual TLS is enabled while the system CA pool is still trusted to validate the client certificates.",
+		Rationale:   "Any client holding a certificate issued by a public CA would be authenticated by the gateway.",
+		Remediation: []string{"Set disable_system_ca_pool to true and declare your private CA in ca_certs."},
+		DocURL:      "https://www.krakend.io/docs/authorization/mutual-authentication/",
+		Tags:        []string{"CWE-295", tagOWASPAuthentication},
+	},
+	"2.1.11": {
+		Title:       "mTLS without CA certificates",
+		Description: "Mutual TLS is enabled but no CA certificates are declared to validate the client certificates.",
+		Rationale:   "Without an explicit CA the client certificates are validated against an implicit trust store that is not under your control.",
+		Remediation: []string{"Declare the CA certificates that issue your client certificates in ca_certs."},
+		DocURL:      "https://www.krakend.io/docs/authorization/mutual-authentication/",
+		Tags:        []string{"CWE-295", tagOWASPAuthentication},
+	},
+	"2.2.1": {
+		Title:       "Version banner exposed",
+		Description: "The service reports its version in the response headers.",
+		Rationale:   "Exposing the exact version helps attackers to select known vulnerabilities.",
+		Remediation: []string{"Set hide_version_header to true in the router options."},
+		DocURL:      "https://www.krakend.io/docs/service-settings/router-options/",
+		Tags:        []string{"CWE-200", tagOWASPMisconfiguration},
+	},
+	"2.2.2": {
+		Title:       "CORS not configured",
+		Description: "The service does not declare a Cross-Origin Resource Sharing policy.",
+		Rationale:   "An explicit CORS policy restricts which browser origins can consume the API.",
+		Remediation: []string{"Add the security/cors namespace with the allowed origins, methods and headers."},
+		DocURL:      "https://www.krakend.io/docs/service-settings/cors/",
+		Tags:        []string{"CWE-942", tagOWASPMisconfiguration},
+	},
+	"2.2.3": {
+		Title:       "All headers forwarded",
+		Description: "Some endpoints forward every input header to their backends.",
+		Rationale:   "Forwarding everything lets clients inject headers the backends trust, such as identity or routing headers.",
+		Remediation: []string{"Replace the wildcard in input_headers with the explicit list of headers each backend needs."},
+		DocURL:      "https://www.krakend.io/docs/endpoints/parameter-forwarding/",
+		Tags:        []string{"CWE-20", tagOWASPPropertyLevelAuthz},
+	},
+	"2.2.4": {
+		Title:       "All query strings forwarded",
+		Description: "Some endpoints forward every query string parameter to their backends.",
+		Rationale:   "Forwarding everything exposes hidden or administrative parameters of the backends to the clients.",
+		Remediation: []string{"Replace the wildcard in input_query_strings with the explicit list of parameters each backend needs."},
+		DocURL:      "https://www.krakend.io/docs/endpoints/parameter-forwarding/",
+		Tags:        []string{"CWE-20", tagOWASPPropertyLevelAuthz},
+	},
+	"2.2.5": {
+		Title:       "gRPC server without services",
+		Description: "The gRPC server is enabled without any service declared.",
+		Rationale:   "An empty server opens a listener that does not serve any purpose.",
+		Remediation: []string{"Declare the services of the gRPC server or remove the server section."},
+		Tags:        []string{tagOWASPInventory},
+		Enterprise:  true,
+	},
+	"2.3.1": {
+		Title:       "Unbounded cache",
+		Description: "Some backends cache responses without limiting the number of items or their size.",
+		Rationale:   "An unbounded in-memory cache can exhaust the memory of the gateway.",
+		Remediation: []string{"Set max_items and max_size in the qos/http-cache namespace of every cached backend."},
+		DocURL:      "https://www.krakend.io/docs/backends/caching/",
+		Tags:        []string{"CWE-770", tagOWASPResourceLimits},
+	},
+	"2.3.2": {
+		Title:       "Shared cache on authenticated endpoints",
+		Description: "Some backends share their cache while the endpoint validates tokens or forwards the Authorization or Cookie headers.",
+		Rationale:   "The cached responses are keyed by URL, so a response for one user can be served to another user requesting the same resource.",
+		Remediation: []string{"Remove shared from the cache of the backends of authenticated endpoints, or cache only public resources."},
+		DocURL:      "https://www.krakend.io/docs/backends/caching/",
+		Tags:        []string{"CWE-524", tagOWASPPropertyLevelAuthz},
+	},
+	"2.3.3": {
+		Title:       "Long cache_ttl on unsafe methods",
+		Description: "Some endpoints with methods that change data declare a cache_ttl longer than a minute.",
+		Rationale:   "Clients and intermediate caches keep the response of a write operation and can return it instead of performing the operation again.",
+		Remediation: []string{"Remove cache_ttl from the endpoints that are not GET, HEAD or OPTIONS."},
+		DocURL:      "https://www.krakend.io/docs/endpoints/caching/",
+		Tags:        []string{"CWE-524"},
+	},
+	"2.3.4": {
+		Title:       "Caching on no-op endpoints",
+		Description: "Some no-op endpoints declare a cache_ttl or cache the responses of their backend.",
+		Rationale:   "No-op endpoints proxy the backend response as is, so the cache_ttl is not applied and the cache does not behave as on the rest of the endpoints.",
+		Remediation: []string{"Use the Cache-Control headers of the backend on no-op endpoints, or change their output_encoding."},
+		DocURL:      "https://www.krakend.io/docs/endpoints/no-op/",
+		Tags:        []string{tagOWASPMisconfiguration},
+	},
+	"2.4.1": {
+		Title:       "Backend error messages returned",
+		Description: "The router returns the error messages of the backends in the response body.",
+		Rationale:   "Backend errors can leak stack traces, internal hostnames or query details to the clients.",
+		Remediation: []string{"Remove return_error_msg from the router options.", "Use error_body or the error handling of each backend to return controlled messages."},
+		DocURL:      "https://www.krakend.io/docs/service-settings/router-options/",
+		Tags:        []string{"CWE-209", tagOWASPMisconfiguration},
+	},
+	"2.4.2": {
+		Title:       "Client IP taken from untrusted headers",
+		Description: "The router reads the client IP from the forwarding headers but does not declare the trusted proxies.",
+		Rationale:   "Any client can set the forwarding headers and impersonate another IP, defeating IP filtering and IP based rate limits.",
+		Remediation: []string{"Declare the trusted_proxies in the router options with the networks of your load balancers.", "Disable forwarded_by_client_ip if there is no proxy in front of the gateway."},
+		DocURL:      "https://www.krakend.io/docs/service-settings/router-options/",
+		Tags:        []string{"CWE-348", tagOWASPMisconfiguration},
+	},
+	"2.4.3": {
+		Title:       "Access log disabled",
+		Description: "The router access log is disabled and no logging component is configured.",
+		Rationale:   "Without any log of the requests there is no trail to investigate incidents or abuse.",
+		Remediation: []string{"Remove disable_access_log from the router options or add a logging component such as telemetry/logging."},
+		DocURL:      "https://www.krakend.io/docs/logging/",
+		Tags:        []string{"CWE-778"},
+	},
+	"2.4.4": {
+		Title:       "Health endpoint disabled",
+		Description: "The router does not expose the health endpoint.",
+		Rationale:   "Orchestrators and load balancers rely on the health endpoint to route traffic only to working instances.",
+		Remediation: []string{"Remove disable_health from the router options.", "Use health_path to expose it under a different path if /__health collides with your API."},
+		DocURL:      "https://www.krakend.io/docs/service-settings/health/",
+	},
+	"2.4.5": {
+		Title:       "Path decoding disabled",
+		Description: "The router matches the routes against the raw, non-decoded path.",
+		Rationale:   "Encoded characters such as %2F can make a request match a different route than the one the backend resolves, bypassing the protections of the intended endpoint.",
+		Remediation: []string{"Remove disable_path_decoding from the router options unless the backends require encoded slashes."},
+		DocURL:      "https://www.krakend.io/docs/service-settings/router-options/",
+		Tags:        []string{"CWE-177", tagOWASPMisconfiguration},
+	},
+	"2.5.1": {
+		Title:       "Plaintext backends",
+		Description: "The gateway terminates TLS but some backends are reached over http on hosts outside the loopback interface and the private ranges. Host names are included, as their addresses are not resolved by the audit.",
+		Rationale:   "The traffic between the gateway and the backends, including credentials and personal data, travels unencrypted even though the clients connect over TLS.",
+		Remediation: []string{"Use the https scheme in the host of the backends, or restrict plaintext traffic to a trusted network."},
+		DocURL:      "https://www.krakend.io/docs/backends/",
+		Tags:        []string{"CWE-319", tagOWASPUnsafeConsumption},
+	},
+	"2.5.2": {
+		Title:       "Mixed backend schemes",
+		Description: "Some backends declare http and https hosts in the same list.",
+		Rationale:   "The load balancer picks any host of the list, so only part of the requests are encrypted.",
+		Remediation: []string{"Use the same scheme, preferably https, for all the hosts of the backend."},
+		DocURL:      "https://www.krakend.io/docs/backends/",
+		Tags:        []string{"CWE-319", tagOWASPMisconfiguration},
+	},
+	"2.5.3": {
+		Title:       "Localhost backends",
+		Description: "Some backends point to localhost or a loopback address.",
+		Rationale:   "Loopback hosts are usually leftovers from development, and only work in production when the service runs next to the gateway.",
+		Remediation: []string{"Replace the loopback hosts with the address of the service in the target environment."},
+		DocURL:      "https://www.krakend.io/docs/backends/",
+		Tags:        []string{tagOWASPInventory},
+	},
+	"2.5.4": {
+		Title:       "Backends without load balancing",
+		Description: "Some backends declare a single static host.",
+		Rationale:   "The gateway can only balance the load and survive a failing instance when it knows several hosts, or resolves them through service discovery.",
+		Remediation: []string{"Add the hosts of the other instances, use the dns service discovery, or point to a load balancer."},
+		DocURL:      "https://www.krakend.io/docs/backends/service-discovery/",
+	},
+	"3.1.1": {
+		Title:       "No bot detector",
+		Description: "The service does not filter automated traffic.",
+		Rationale:   "Bots and scrapers consume resources and are a common source of abuse.",
+		Remediation: []string{"Add the security/bot-detector namespace with the allow, deny and pattern lists that fit your traffic."},
+		DocURL:      "https://www.krakend.io/docs/throttling/botdetector/",
+		Tags:        []string{"CWE-799", tagOWASPResourceLimits},
+	},
+	"3.1.2": {
+		Title:       "No rate limiting",
+		Description: "Neither the service, the endpoints nor the backends limit the rate of requests.",
+		Rationale:   "Without rate limits, a single client can exhaust the capacity of the gateway and its backends.",
+		Remediation: []string{"Add a service level rate limit and endpoint rate limits for the expensive or sensitive endpoints."},
+		DocURL:      "https://www.krakend.io/docs/endpoints/rate-limit/",
+		Tags:        []string{"CWE-770", tagOWASPResourceLimits},
+	},
+	"3.1.3": {
+		Title:       "No circuit breaker",
+		Description: "None of the endpoints or backends uses a circuit breaker.",
+		Rationale:   "Failing backends keep receiving traffic, cascading the failure to the gateway and the clients.",
+		Remediation: []string{"Add the qos/circuit-breaker namespace to the backends."},
+		DocURL:      "https://www.krakend.io/docs/backends/circuit-breaker/",
+		Tags:        []string{"CWE-400", tagOWASPResourceLimits},
+	},
+	"3.1.4": {
+		Title:       "Write endpoints without rate limit",
+		Description: "Some endpoints with methods other than GET, HEAD or OPTIONS do not declare an effective rate limit.",
+		Rationale:   "Write operations are the most expensive for the backends and the most abused by automated clients, and a service-wide limit does not protect them individually.",
+		Remediation: []string{"Add a qos/ratelimit/router section with max_rate, or client_max_rate and a strategy, to the write endpoints."},
+		DocURL:      "https://www.krakend.io/docs/endpoints/rate-limit/",
+		Tags:        []string{"CWE-770", tagOWASPResourceLimits},
+	},
+	"3.1.5": {
+		Title:       "Client rate limit without strategy",
+		Description: "Some rate limits declare client_max_rate without a known strategy.",
+		Rationale:   "The gateway cannot tell the clients apart without a strategy and skips the per-client limit, so it is never applied.",
+		Remediation: []string{"Set the strategy to ip, header or param."},
+		DocURL:      "https://www.krakend.io/docs/endpoints/rate-limit/",
+		Tags:        []string{tagOWASPResourceLimits, tagOWASPMisconfiguration},
+	},
+	"3.1.6": {
+		Title:       "Client rate limit without key",
+		Description: "Some rate limits use the header or param strategy without declaring the key.",
+		Rationale:   "Without a key every request is identified by the same empty value, so all the clients share a single bucket and throttle each other.",
+		Remediation: []string{"Set the key to the header or parameter identifying the client, such as Authorization or X-Api-Key."},
+		DocURL:      "https://www.krakend.io/docs/endpoints/rate-limit/",
+		Tags:        []string{tagOWASPMisconfiguration},
+	},
+	"3.1.7": {
+		Title:       "Client rate above the endpoint rate",
+		Description: "Some rate limits allow a single client more requests than the whole endpoint.",
+		Rationale:   "The endpoint limit is reached first, so one client can consume the whole capacity and the per-client limit has no effect.",
+		Remediation: []string{"Set a client_max_rate lower than the max_rate."},
+		DocURL:      "https://www.krakend.io/docs/endpoints/rate-limit/",
+		Tags:        []string{tagOWASPResourceLimits},
+	},
+	"3.2.1": {
+		Title:       "Inert bot detector",
+		Description: "A bot detector declares no allow, deny or pattern lists and does not reject empty user agents.",
+		Rationale:   "The configuration looks protected against bots while every request goes through.",
+		Remediation: []string{"Add the deny list or the patterns of the bots to block.", "Set empty_user_agent_is_bot to reject requests without user agent."},
+		DocURL:      "https://www.krakend.io/docs/throttling/botdetector/",
+		Tags:        []string{tagOWASPMisconfiguration},
+	},
+	"3.2.2": {
+		Title:       "Bot detector without cache",
+		Description: "A bot detector evaluates many patterns without caching the results.",
+		Rationale:   "Every pattern is a regular expression evaluated on each request, adding latency and CPU usage under load.",
+		Remediation: []string{"Set cache_size to keep the results of the most frequent user agents."},
+		DocURL:      "https://www.krakend.io/docs/throttling/botdetector/",
+		Tags:        []string{"CWE-1176", tagOWASPResourceLimits},
+	},
+	"3.2.3": {
+		Title:       "Inconsistent bot detection",
+		Description: "Some endpoints declare a bot detector while others do not, and there is none at service level.",
+		Rationale:   "Bots can reach the same data through the unprotected endpoints.",
+		Remediation: []string{"Move the bot detector to the service level, or add it to the remaining endpoints."},
+		DocURL:      "https://www.krakend.io/docs/throttling/botdetector/",
+		Tags:        []string{tagOWASPMisconfiguration},
+	},
+	"3.3.1": {
+		Title:       "Timeout above 3 seconds",
+		Description: "Some endpoints wait more than 3 seconds for their backends.",
+		Rationale:   "Long timeouts keep connections and goroutines busy, reducing the capacity of the gateway under load.",
+		Remediation: []string{"Lower the timeout of the endpoint to the latency the clients actually need."},
+		DocURL:      "https://www.krakend.io/docs/throttling/timeouts/",
+		Tags:        []string{"CWE-400", tagOWASPResourceLimits},
+	},
+	"3.3.2": {
+		Title:       "Timeout above 5 seconds",
+		Description: "Some endpoints wait more than 5 seconds for their backends.",
+		Rationale:   "Long timeouts keep connections and goroutines busy, reducing the capacity of the gateway under load.",
+		Remediation: []string{"Lower the timeout of the endpoint to the latency the clients actually need."},
+		DocURL:      "https://www.krakend.io/docs/throttling/timeouts/",
+		Tags:        []string{"CWE-400", tagOWASPResourceLimits},
+	},
+	"3.3.3": {
+		Title:       "Timeout above 30 seconds",
+		Description: "Some endpoints wait more than 30 seconds for their backends.",
+		Rationale:   "Such timeouts make the gateway an easy target for slow request attacks.",
+		Remediation: []string{"Lower the timeout of the endpoint, or move long running operations to asynchronous flows."},
+		DocURL:      "https://www.krakend.io/docs/throttling/timeouts/",
+		Tags:        []string{"CWE-400", tagOWASPResourceLimits},
+	},
+	"3.3.4": {
+		Title:       "Timeout above 1 minute",
+		Description: "Some endpoints wait more than a minute for their backends.",
+		Rationale:   "Such timeouts make the gateway an easy target for slow request attacks.",
+		Remediation: []string{"Lower the timeout of the endpoint, or move long running operations to asynchronous flows."},
+		DocURL:      "https://www.krakend.io/docs/throttling/timeouts/",
+		Tags:        []string{"CWE-400", tagOWASPResourceLimits},
+	},
+	"3.3.5": {
+		Title:       "No read header timeout",
+		Description: "The http server does not limit the time to read the request headers, as neither read_header_timeout nor read_timeout are set.",
+		Rationale:   "Clients sending the headers slowly (slowloris) keep the connections open indefinitely and exhaust the resources of the gateway.",
+		Remediation: []string{"Set read_header_timeout at the service level, for instance to a few seconds."},
+		DocURL:      "https://www.krakend.io/docs/service-settings/http-server-settings/",
+		Tags:        []string{"CWE-400", tagOWASPResourceLimits},
+	},
+	"3.3.6": {
+		Title:       "Write timeout shorter than the endpoints",
+		Description: "The write_timeout of the http server is shorter than the timeout of some endpoints.",
+		Rationale:   "The server closes the connection before the slowest endpoints can write their response, so clients receive truncated or empty responses.",
+		Remediation: []string{"Set a write_timeout longer than the longest endpoint timeout, or reduce the timeout of the endpoints."},
+		DocURL:      "https://www.krakend.io/docs/service-settings/http-server-settings/",
+	},
+	"3.3.7": {
+		Title:       "Unbounded idle connections",
+		Description: "The http server never closes the idle keep-alive connections.",
+		Rationale:   "Without idle_timeout or read_timeout, which the http server uses when the former is not set, idle connections accumulate until the gateway runs out of file descriptors.",
+		Remediation: []string{"Set idle_timeout at the service level."},
+		DocURL:      "https://www.krakend.io/docs/service-settings/http-server-settings/",
+		Tags:        []string{"CWE-400", tagOWASPResourceLimits},
+	},
+	"3.3.8": {
+		Title:       "Large header size limit",
+		Description: "The http server accepts request headers larger than 1MB.",
+		Rationale:   "Large headers consume memory on every connection and are rarely needed by legitimate clients.",
+		Remediation: []string{"Remove max_header_bytes or lower it to 1MB or less."},
+		DocURL:      "https://www.krakend.io/docs/service-settings/http-server-settings/",
+		Tags:        []string{"CWE-770", tagOWASPResourceLimits},
+	},
+	"4.1.1": {
+		Title:       "No metrics",
+		Description: "The service does not export metrics.",
+		Rationale:   "Without metrics, abuse, saturation and failures go unnoticed.",
+		Remediation: []string{"Enable OpenTelemetry with at least one exporter of metrics."},
+		DocURL:      "https://www.krakend.io/docs/telemetry/opentelemetry/",
+		Tags:        []string{"CWE-778"},
+	},
+	"4.1.2": {
+		Title:       "Unnamed configuration",
+		Description: "The configuration has no name to identify it in the metrics.",
+		Rationale:   "Named configurations make it easy to tell apart the metrics of several gateways.",
+		Remediation: []string{"Set the name of the service."},
+	},
+	"4.1.3": {
+		Title:       "Duplicated telemetry",
+		Description: "Several telemetry components or exporters collect the same metrics.",
+		Rationale:   "Duplicated instrumentation adds overhead to every request without adding information.",
+		Remediation: []string{"Keep a single metrics pipeline, preferably OpenTelemetry."},
+		DocURL:      "https://www.krakend.io/docs/telemetry/opentelemetry/",
+		Tags:        []string{"CWE-400"},
+	},
+	"4.1.4": {
+		Title:       "Short metric reporting period",
+		Description: "OpenTelemetry reports the metrics more often than every 10 seconds.",
+		Rationale:   "Very frequent reports multiply the load on the gateway and the collectors without adding useful resolution.",
+		Remediation: []string{"Set metric_reporting_period to 30 seconds or more, the default value."},
+		DocURL:      "https://www.krakend.io/docs/telemetry/opentelemetry/",
+		Tags:        []string{"CWE-400"},
+	},
+	"4.2.1": {
+		Title:       "No tracing",
+		Description: "The service does not export traces.",
+		Rationale:   "Traces are needed to troubleshoot the latency and the failures of the requests across services.",
+		Remediation: []string{"Enable OpenTelemetry with at least one exporter of traces."},
+		DocURL:      "https://www.krakend.io/docs/telemetry/opentelemetry/",
+		Tags:        []string{"CWE-778"},
+	},
+	"4.2.2": {
+		Title:       "All traces sampled",
+		Description: "OpenTelemetry samples every request (trace_sample_rate of 1).",
+		Rationale:   "Tracing all the requests in production adds overhead to each of them and overloads the tracing backend.",
+		Remediation: []string{"Lower trace_sample_rate to the fraction of the traffic you need, such as 0.1 for a 10%."},
+		DocURL:      "https://www.krakend.io/docs/telemetry/opentelemetry/",
+		Tags:        []string{"CWE-400"},
+	},
+	"4.3.1": {
+		Title:       "Default logging",
+		Description: "The service does not use the improved logging component.",
+		Rationale:   "Structured and leveled logs are easier to parse, ship and alert on.",
+		Remediation: []string{"Add the telemetry/logging namespace, optionally with the gelf or logstash formats."},
+		DocURL:      "https://www.krakend.io/docs/logging/",
+		Tags:        []string{"CWE-778"},
+	},
+	"4.4.1": {
+		Title:       "OpenTelemetry over clear text",
+		Description: "Some OTLP exporters send the data to a collector outside the loopback interface and the private ranges using the http scheme, which is also the default for the hosts without a scheme.",
+		Rationale:   "Telemetry carries paths, headers and timing information that can be read or altered in transit.",
+		Remediation: []string{"Use an https endpoint for the remote collectors, or send the data to a sidecar collector on the loopback interface."},
+		DocURL:      "https://www.krakend.io/docs/telemetry/opentelemetry/",
+		Tags:        []string{"CWE-319", tagOWASPMisconfiguration},
+	},
+	"4.4.2": {
+		Title:       "All exporters disabled",
+		Description: "Every OpenTelemetry exporter has its metrics and traces disabled.",
+		Rationale:   "The instrumentation runs on every request but its data is never exported.",
+		Remediation: []string{"Enable the metrics or the traces of the exporters you need, or remove the telemetry/opentelemetry namespace."},
+		DocURL:      "https://www.krakend.io/docs/telemetry/opentelemetry/",
+	},
+	"5.1.1": {
+		Title:       "RESTful checks disabled",
+		Description: "The service accepts endpoint definitions that do not follow a RESTful structure.",
+		Rationale:   "Non RESTful paths are harder to secure, document and maintain.",
+		Remediation: []string{"Remove disable_rest and adapt the endpoint definitions."},
+		Tags:        []string{tagOWASPInventory},
+	},
+	"5.1.2": {
+		Title:       "Debug endpoint enabled",
+		Description: "The /__debug/ endpoint is exposed.",
+		Rationale:   "The debug endpoint reveals the received requests, including their headers, to any client.",
+		Remediation: []string{"Remove debug_endpoint from production configurations."},
+		DocURL:      "https://www.krakend.io/docs/endpoints/debug-endpoint/",
+		Tags:        []string{"CWE-489", tagOWASPMisconfiguration},
+	},
+	"5.1.3": {
+		Title:       "Echo endpoint enabled",
+		Description: "The /__echo/ endpoint is exposed.",
+		Rationale:   "The echo endpoint reflects the received requests, including their headers, to any client.",
+		Remediation: []string{"Remove echo_endpoint from production configurations."},
+		Tags:        []string{"CWE-489", tagOWASPMisconfiguration},
+	},
+	"5.1.4": {
+		Title:       "Wildcard endpoints",
+		Description: "Some endpoints match any path under a prefix.",
+		Rationale:   "Wildcards expose every resource of the backend, including the ones that were never meant to be public.",
+		Remediation: []string{"Declare the endpoints explicitly."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/endpoints/wildcard/",
+		Tags:        []string{tagOWASPInventory},
+	},
+	"5.1.5": {
+		Title:       "Catch-all endpoint",
+		Description: "The configuration declares the /__catchall endpoint.",
+		Rationale:   "Any request not matching an explicit endpoint reaches the backend, bypassing the inventory of the API.",
+		Remediation: []string{"Declare the endpoints explicitly and remove /__catchall."},
+		Tags:        []string{tagOWASPInventory},
+	},
+	"5.1.6": {
+		Title:       "Multiple write methods",
+		Description: "Some endpoints call several backends with unsafe methods.",
+		Rationale:   "The gateway cannot guarantee the consistency of several writes, and a partial failure leaves the data in an unknown state.",
+		Remediation: []string{"Keep a single write per endpoint and move the orchestration to a service or an async flow."},
+		Tags:        []string{tagOWASPUnsafeConsumption},
+	},
+	"5.1.7": {
+		Title:       "Sequential proxy",
+		Description: "Some endpoints chain their backend calls.",
+		Rationale:   "Sequential calls add up their latencies and couple the availability of the backends.",
+		Remediation: []string{"Call the backends concurrently whenever they do not depend on each other."},
+		DocURL:      "https://www.krakend.io/docs/endpoints/sequential-proxy/",
+	},
+	"5.1.8": {
+		Title:       "Duplicated routes",
+		Description: "Some endpoints declare the same method and path as a previous endpoint.",
+		Rationale:   "Only one of the declarations can serve the requests, so the behavior of the other one, including its protections, is never applied.",
+		Remediation: []string{"Remove the duplicated endpoints or merge their configuration."},
+		DocURL:      "https://www.krakend.io/docs/endpoints/",
+		Tags:        []string{tagOWASPInventory},
+	},
+	"5.1.9": {
+		Title:       "Routes overlapping a wildcard",
+		Description: "Some endpoints are declared under the path of a wildcard endpoint with the same method.",
+		Rationale:   "The same request path is covered by two endpoints with different configurations, so it is hard to tell which protections apply.",
+		Remediation: []string{"Narrow the wildcard endpoint, or move the explicit endpoints out of its path."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/endpoints/wildcard/",
+		Tags:        []string{tagOWASPInventory},
+	},
+	"5.1.10": {
+		Title:       "Conflicting route parameters",
+		Description: "Some endpoints use a different parameter name at the same position of a path shared with another endpoint.",
+		Rationale:   "The router does not accept different names for the same parameter segment and the service fails to register the routes.",
+		Remediation: []string{"Rename the parameters so all the endpoints sharing the prefix use the same name."},
+		DocURL:      "https://www.krakend.io/docs/endpoints/",
+	},
+	"5.2.1": {
+		Title:       "Endpoints without backends",
+		Description: "Some endpoints do not declare any backend.",
+		Rationale:   "Such endpoints cannot work and usually reveal an incomplete configuration.",
+		Remediation: []string{"Add the backends of the endpoint or remove it."},
+		Tags:        []string{tagOWASPInventory},
+	},
+	"5.2.2": {
+		Title:       "Single backend per endpoint",
+		Description: "Every endpoint calls a single backend.",
+		Rationale:   "Aggregating backends at the gateway reduces the number of round trips of the clients.",
+		Remediation: []string{"Merge the endpoints consumed together by the same clients."},
+	},
+	"5.2.3": {
+		Title:       "No-op encoding everywhere",
+		Description: "Every endpoint proxies the responses of the backends without manipulation.",
+		Rationale:   "No-op endpoints couple the clients to the backends and disable most of the data manipulation features.",
+		Remediation: []string{"Use the json encoding in the endpoints that can benefit from filtering or aggregation."},
+		DocURL:      "https://www.krakend.io/docs/endpoints/content-types/",
+	},
+	"5.3.1": {
+		Title:       "Unbounded websocket messages",
+		Description: "Some websocket endpoints accept messages larger than 1MB.",
+		Rationale:   "Large messages are buffered in memory by the gateway, so a few clients can exhaust it.",
+		Remediation: []string{"Set max_message_size to the largest message your application needs."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/websockets/",
+		Tags:        []string{"CWE-770", tagOWASPResourceLimits},
+		Enterprise:  true,
+	},
+	"5.3.2": {
+		Title:       "Websocket error details returned",
+		Description: "Some websocket endpoints return the details of the errors to the clients.",
+		Rationale:   "Error details can leak internal hostnames, addresses or protocol information.",
+		Remediation: []string{"Remove return_error_details from the websocket configuration."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/websockets/",
+		Tags:        []string{"CWE-209", tagOWASPMisconfiguration},
+		Enterprise:  true,
+	},
+	"5.3.3": {
+		Title:       "Websocket ping after pong deadline",
+		Description: "The ping_period of some websocket endpoints is not lower than their pong_wait.",
+		Rationale:   "The pong answer cannot arrive before the deadline, so healthy connections are dropped.",
+		Remediation: []string{"Set ping_period to about 90% of pong_wait, such as 54s for a pong_wait of 60s."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/websockets/",
+		Enterprise:  true,
+	},
+	"5.3.4": {
+		Title:       "Large websocket buffers",
+		Description: "The buffers of some websocket endpoints can retain more than 16MB per connection.",
+		Rationale:   "The memory of the buffers is multiplied by the number of open connections.",
+		Remediation: []string{"Reduce message_buffer_size, max_message_size or the read and write buffer sizes."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/websockets/",
+		Tags:        []string{"CWE-770", tagOWASPResourceLimits},
+		Enterprise:  true,
+	},
+	"5.3.5": {
+		Title:       "Unlimited websocket retries",
+		Description: "Some websocket endpoints retry the connection to the backend forever.",
+		Rationale:   "Endless reconnections to a broken backend keep the client connections open and load the backend when it recovers.",
+		Remediation: []string{"Set max_retries to a positive number along with a backoff_strategy."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/websockets/",
+		Enterprise:  true,
+	},
+	"5.3.6": {
+		Title:       "Unauthenticated websocket",
+		Description: "Some websocket endpoints do not authenticate their clients.",
+		Rationale:   "Websocket connections are long lived and bidirectional, and anyone can open them without authentication.",
+		Remediation: []string{"Add the auth/validator namespace, or another authentication component, to the websocket endpoints."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/websockets/",
+		Tags:        []string{"CWE-306", tagOWASPAuthentication},
+		Enterprise:  true,
+	},
+	"5.3.7": {
+		Title:       "Websocket without rate limit",
+		Description: "Some websocket endpoints are not covered by any rate limit.",
+		Rationale:   "Clients can open as many long lived connections as they want, exhausting the gateway and the backend.",
+		Remediation: []string{"Add the qos/ratelimit/router namespace to the websocket endpoints, or a service rate limit."},
+		DocURL:      "https://www.krakend.io/docs/endpoints/rate-limit/",
+		Tags:        []string{"CWE-770", tagOWASPResourceLimits},
+		Enterprise:  true,
+	},
+	"6.1.1": {
+		Title:       "Sequential start of many agents",
+		Description: "More than 10 async agents start sequentially.",
+		Rationale:   "Starting many agents one after the other delays the readiness of the gateway.",
+		Remediation: []string{"Remove sequential_start or reduce the number of agents."},
+		DocURL:      "https://www.krakend.io/docs/async/",
+	},
+	"5.4.1": {
+		Title:       "Lua open libraries",
+		Description: "Some Lua scripts run with the standard libraries open.",
+		Rationale:   "The io and os libraries give scripts, and any request data they evaluate, access to the filesystem and to the commands of the host.",
+		Remediation: []string{"Remove allow_open_libs and use the helpers provided by the gateway instead."},
+		DocURL:      "https://www.krakend.io/docs/endpoints/lua/",
+		Tags:        []string{"CWE-94", tagOWASPMisconfiguration},
+	},
+	"5.4.2": {
+		Title:       "Lua live reload",
+		Description: "Some Lua components read their sources from disk on every request.",
+		Rationale:   "Reading the files on each request adds latency, and a change on disk alters production behavior without a deploy.",
+		Remediation: []string{"Remove live from the Lua configuration outside of development environments."},
+		DocURL:      "https://www.krakend.io/docs/endpoints/lua/",
+		Tags:        []string{tagOWASPMisconfiguration},
+	},
+	"5.4.3": {
+		Title:       "Lua in every layer",
+		Description: "Some endpoints run Lua at the router, the proxy and the backend layers.",
+		Rationale:   "Scripts spread across layers are hard to follow and each layer adds a virtual machine to the request.",
+		Remediation: []string{"Consolidate the logic in a single layer, or replace it with declarative components where possible."},
+		DocURL:      "https://www.krakend.io/docs/endpoints/lua/",
+	},
+	"5.4.4": {
+		Title:       "Large inline Lua code",
+		Description: "Some Lua components declare more than 1KB of inline code in pre or post.",
+		Rationale:   "Inline code escaped in JSON is hard to review, test and reuse.",
+		Remediation: []string{"Move the code to a Lua file listed in sources and call its functions from pre and post."},
+		DocURL:      "https://www.krakend.io/docs/endpoints/lua/",
+	},
+	"5.5.1": {
+		Title:       "Response schema error without content type",
+		Description: "Some response schema validations return a custom error body without declaring its content type.",
+		Rationale:   "Clients cannot parse an error body reliably when its content type does not describe it.",
+		Remediation: []string{"Add the content_type of the body under error, for instance application/json."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/endpoints/response-schema-validator/",
+		Enterprise:  true,
+	},
+	"5.5.2": {
+		Title:       "Response schema error with a non-error status",
+		Description: "Some response schema validations return a status code outside the 4xx and 5xx ranges.",
+		Rationale:   "A successful or redirect status hides the failed validation from clients, caches and monitoring.",
+		Remediation: []string{"Set an error status under error, usually 500 or 502 when the backend returns unexpected content."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/endpoints/response-schema-validator/",
+		Tags:        []string{"CWE-393"},
+		Enterprise:  true,
+	},
+	"5.5.3": {
+		Title:       "Large response schema",
+		Description: "Some response schemas declare more than 10KB inline.",
+		Rationale:   "The validation runs on every response, so large schemas add latency and CPU to all the requests of the endpoint.",
+		Remediation: []string{"Validate only the fields the clients depend on, or split the endpoint."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/endpoints/response-schema-validator/",
+		Tags:        []string{tagOWASPResourceLimits},
+		Enterprise:  true,
+	},
+	"5.5.4": {
+		Title:       "Duplicated response schema validation",
+		Description: "Some endpoints validate the responses with both the deprecated plugin and the new namespace.",
+		Rationale:   "Each response is validated twice, and the two schemas can drift apart and reject different payloads.",
+		Remediation: []string{"Remove response-schema-validator from the req-resp-modifier plugin and keep validation/response-json-schema."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/endpoints/response-schema-validator/#migration-from-old-plugin",
+		Enterprise:  true,
+	},
+	"5.6.1": {
+		Title:       "Many regexp modifiers",
+		Description: "Some endpoints apply more than 5 regexp modifiers to the response body.",
+		Rationale:   "Every regular expression is evaluated on every response, adding latency and CPU usage that grows with the size of the payload.",
+		Remediation: []string{"Replace the regexp modifiers with literal, upper, lower or trim when possible, or transform the data in the backend."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/endpoints/content-replacer/",
+		Tags:        []string{tagOWASPResourceLimits},
+		Enterprise:  true,
+	},
+	"5.6.2": {
+		Title:       "Response body modifiers on no-op endpoints",
+		Description: "Some endpoints with no-op encoding declare response body modifiers.",
+		Rationale:   "No-op endpoints proxy the response as is, so the modifiers are never applied and the fields they should mask reach the clients.",
+		Remediation: []string{"Change the output_encoding of the endpoint, or remove the modifiers and transform the data in the backend."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/endpoints/content-replacer/",
+		Tags:        []string{tagOWASPMisconfiguration},
+		Enterprise:  true,
+	},
+	"5.6.3": {
+		Title:       "Long modifier chains on fan-out endpoints",
+		Description: "Some endpoints with 3 or more backends apply more than 10 response body modifiers.",
+		Rationale:   "The modifiers of the endpoint and of each backend run on every request, so their cost adds up with the number of backends.",
+		Remediation: []string{"Keep the modifiers on the backends that need them, or move the transformation to the backends."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/endpoints/content-replacer/",
+		Tags:        []string{tagOWASPResourceLimits},
+		Enterprise:  true,
+	},
+	"7.1.1": {
+		Title:       "Deprecated virtualhost plugin",
+		Description: "The configuration uses the virtualhost plugin.",
+		Rationale:   "Deprecated plugins do not receive fixes and will be removed.",
+		Remediation: []string{"Move the configuration to the server/virtualhost namespace."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/service-settings/virtual-hosts/#upgrading-from-the-old-plugin-before-v24",
+		Tags:        []string{"CWE-477"},
+		Enterprise:  true,
+	},
+	"7.1.2": {
+		Title:       "Deprecated static-filesystem server plugin",
+		Description: "The configuration uses the static-filesystem server plugin.",
+		Rationale:   "Deprecated plugins do not receive fixes and will be removed.",
+		Remediation: []string{"Move the configuration to the server/static-filesystem namespace."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/endpoints/serve-static-content/#upgrading-from-the-old-plugin-before-v24",
+		Tags:        []string{"CWE-477"},
+		Enterprise:  true,
+	},
+	"7.1.3": {
+		Title:       "Deprecated basic-auth plugin",
+		Description: "The configuration uses the basic-auth plugin.",
+		Rationale:   "Deprecated plugins do not receive fixes and will be removed.",
+		Remediation: []string{"Move the configuration to the auth/basic namespace."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/authentication/basic-authentication/",
+		Tags:        []string{"CWE-477"},
+		Enterprise:  true,
+	},
+	"7.1.4": {
+		Title:       "Deprecated wildcard plugin",
+		Description: "The configuration uses the wildcard plugin.",
+		Rationale:   "Deprecated plugins do not receive fixes and will be removed.",
+		Remediation: []string{"Move the configuration to the native wildcard support."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/endpoints/wildcard/#upgrading-from-the-old-wildcard-plugin-before-v23",
+		Tags:        []string{"CWE-477"},
+		Enterprise:  true,
+	},
+	"7.1.5": {
+		Title:       "Deprecated http-proxy plugin",
+		Description: "The configuration uses the http-proxy plugin.",
+		Rationale:   "Deprecated plugins do not receive fixes and will be removed.",
+		Remediation: []string{"Move the configuration to the backend/http/client namespace."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/backends/http-proxy/#migration-from-old-plugin",
+		Tags:        []string{"CWE-477"},
+		Enterprise:  true,
+	},
+	"7.1.6": {
+		Title:       "Deprecated static-filesystem client plugin",
+		Description: "The configuration uses the static-filesystem client plugin.",
+		Rationale:   "Deprecated plugins do not receive fixes and will be removed.",
+		Remediation: []string{"Move the configuration to the backend/static-filesystem namespace."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/endpoints/serve-static-content/#upgrading-from-the-old-plugin-before-v24",
+		Tags:        []string{"CWE-477"},
+		Enterprise:  true,
+	},
+	"7.1.7": {
+		Title:       "Deprecated no-redirect plugin",
+		Description: "The configuration uses the no-redirect plugin.",
+		Rationale:   "Deprecated plugins do not receive fixes and will be removed.",
+		Remediation: []string{"Move the configuration to the backend/http/client namespace."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/backends/client-redirect/#migration-from-old-plugin",
+		Tags:        []string{"CWE-477"},
+		Enterprise:  true,
+	},
+	"7.1.8": {
+		Title:       "Deprecated content-replacer plugin",
+		Description: "The configuration uses the content-replacer plugin.",
+		Rationale:   "Deprecated plugins do not receive fixes and will be removed.",
+		Remediation: []string{"Move the configuration to the modifier/response-body namespace."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/endpoints/content-replacer/#migration-from-old-plugin",
+		Tags:        []string{"CWE-477"},
+		Enterprise:  true,
+	},
+	"7.1.9": {
+		Title:       "Deprecated response-schema-validator plugin",
+		Description: "The configuration uses the response-schema-validator plugin.",
+		Rationale:   "Deprecated plugins do not receive fixes and will be removed.",
+		Remediation: []string{"Move the configuration to the validation/response-json-schema namespace."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/endpoints/response-schema-validator/#migration-from-old-plugin",
+		Tags:        []string{"CWE-477"},
+		Enterprise:  true,
+	},
+	"7.2.1": {
+		Title:       "Deprecated Google Analytics telemetry",
+		Description: "The configuration uses the telemetry/ganalytics component.",
+		Rationale:   "Deprecated components do not receive fixes and will be removed.",
+		Remediation: []string{"Move the telemetry to OpenTelemetry."},
+		DocURL:      "https://www.krakend.io/docs/telemetry/opentelemetry/",
+		Tags:        []string{"CWE-477"},
+		Enterprise:  true,
+	},
+	"7.2.2": {
+		Title:       "Deprecated Instana telemetry",
+		Description: "The configuration uses the telemetry/instana component.",
+		Rationale:   "Deprecated components do not receive fixes and will be removed.",
+		Remediation: []string{"Move the telemetry to OpenTelemetry."},
+		DocURL:      "https://www.krakend.io/docs/telemetry/opentelemetry/",
+		Tags:        []string{"CWE-477"},
+		Enterprise:  true,
+	},
+	"7.2.3": {
+		Title:       "Deprecated OpenCensus telemetry",
+		Description: "The configuration uses the telemetry/opencensus component.",
+		Rationale:   "Deprecated components do not receive fixes and will be removed.",
+		Remediation: []string{"Move the telemetry to OpenTelemetry."},
+		DocURL:      "https://www.krakend.io/docs/telemetry/opencensus/#transition-from-opencensus",
+		Tags:        []string{"CWE-477"},
+	},
+	"7.2.4": {
+		Title:       "Deprecated InfluxDB telemetry",
+		Description: "The configuration uses the telemetry/influx component.",
+		Rationale:   "Deprecated components do not receive fixes and will be removed.",
+		Remediation: []string{"Move the telemetry to OpenTelemetry."},
+		DocURL:      "https://www.krakend.io/docs/telemetry/influxdb/",
+		Tags:        []string{"CWE-477"},
+	},
+	"7.3.1": {
+		Title:       "Deprecated TLS key fields",
+		Description: "The tls section uses the private_key and public_key fields.",
+		Rationale:   "The single key pair fields are deprecated in favour of the keys array, which supports several certificates.",
+		Remediation: []string{"Move the key pair to the keys array of the tls section."},
+		DocURL:      "https://www.krakend.io/docs/service-settings/tls/",
+		Tags:        []string{"CWE-477"},
+	},
+	"8.1.1": {
+		Title:       "Unauthenticated AI endpoint",
+		Description: "Some endpoints consuming an LLM or serving MCP tools do not authenticate their clients.",
+		Rationale:   "Anyone can consume the paid LLM credentials of the gateway or invoke the tools of the MCP servers.",
+		Remediation: []string{"Add the auth/validator or auth/api-keys namespace to the LLM and MCP endpoints."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/ai-gateway/",
+		Tags:        []string{"CWE-306", tagOWASPAuthentication},
+		Enterprise:  true,
+	},
+	"8.1.2": {
+		Title:       "AI endpoint without limits",
+		Description: "Some endpoints consuming an LLM or serving MCP tools have no rate limit or quota.",
+		Rationale:   "LLM requests are billed per token, so a single client can generate unbounded costs.",
+		Remediation: []string{"Add a rate limit to the endpoint or a governance/quota to the endpoint or its backend."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/ai-gateway/",
+		Tags:        []string{"CWE-770", tagOWASPResourceLimits},
+		Enterprise:  true,
+	},
+	"8.1.3": {
+		Title:       "Long AI timeout",
+		Description: "Some endpoints consuming an LLM or serving MCP tools wait more than 2 minutes for a response.",
+		Rationale:   "Long generations keep connections and memory busy, and the clients usually give up before.",
+		Remediation: []string{"Lower the timeout of the endpoint and limit the size of the generated output in the provider settings."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/ai-gateway/",
+		Tags:        []string{"CWE-400", tagOWASPResourceLimits},
+		Enterprise:  true,
+	},
+	"8.1.4": {
+		Title:       "Outdated LLM API version",
+		Description: "Some LLM backends declare an API version of the provider older than the ones supported by the gateway.",
+		Rationale:   "Outdated API versions are retired by the providers and the gateway does not adapt their requests. Newer versions unknown to the audit are not reported.",
+		Remediation: []string{"Move the provider settings to the API version supported by the gateway."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/ai-gateway/",
+		Tags:        []string{tagOWASPInventory},
+		Enterprise:  true,
+	},
+	"8.2.1": {
+		Title:       "Large MCP server",
+		Description: "Some MCP servers expose more than 20 tools.",
+		Rationale:   "Models choose worse among many tools, and every tool widens the surface an agent can act on.",
+		Remediation: []string{"Split the tools into several MCP servers by domain, and expose only the tools each agent needs."},
+		DocURL:      "https://www.krakend.io/docs/enterprise/ai-gateway/",
+		Tags:        []string{tagOWASPInventory},
+		Enterprise:  true,
+	},
+}
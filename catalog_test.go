@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestListRules(t *testing.T) {
+	rules := ListRules()
+	if len(rules) != len(ruleSet) {
+		t.Errorf("unexpected number of rules. have: %d, want: %d", len(rules), len(ruleSet))
+	}
+
+	for _, r := range rules {
+		if r.Title == "" || r.Description == "" || r.Rationale == "" || len(r.Remediation) == 0 {
+			t.Errorf("rule %s is not documented: %+v", r.ID, r.RuleMetadata)
+		}
+		if r.Section != ruleSection(r.ID) || r.Category == "" {
+			t.Errorf("rule %s has an unexpected section: %s (%s)", r.ID, r.Section, r.Category)
+		}
+		if !r.Severity.IsValid() || r.Message == "" {
+			t.Errorf("rule %s has an unexpected recommendation: %+v", r.ID, r)
+		}
+	}
+
+	for id := range ruleMetadata {
+		if indexOf(ruleSet, id) < 0 {
+			t.Errorf("the metadata of %s does not belong to any rule", id)
+		}
+	}
+}
+
+func TestAuditor_WriteCatalog(t *testing.T) {
+	custom := NewRule("99.1.1", SeverityLow, "Declare at least one endpoint.", hasNoEndpoints)
+	custom.Metadata = RuleMetadata{
+		Title:       "No endpoints",
+		Description: "The configuration does not declare any endpoint.",
+		Tags:        []string{"internal"},
+	}
+	a, err := NewAuditor(WithRules(custom))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	if err := a.WriteCatalog(buf); err != nil {
+		t.Error(err)
+		return
+	}
+
+	var catalog []RuleInfo
+	if err := json.Unmarshal(buf.Bytes(), &catalog); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(catalog) != len(ruleSet)+1 {
+		t.Errorf("unexpected number of rules. have: %d, want: %d", len(catalog), len(ruleSet)+1)
+		return
+	}
+
+	r := catalog[len(catalog)-1]
+	if r.ID != "99.1.1" || r.Title != "No endpoints" || r.Section != "99" || r.Category != "" || len(r.Tags) != 1 {
+		t.Errorf("unexpected custom rule: %+v", r)
+	}
+
+	r = catalog[0]
+	if r.ID != "1.1.1" || r.Category != "Security" || !r.Enterprise || r.DocURL == "" {
+		t.Errorf("unexpected built-in rule: %+v", r)
+	}
+}
@@ -0,0 +1,99 @@
+package audit
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/luraproject/lura/v2/config"
+)
+
+// Scope is the list of reference tokens of the JSON pointer identifying the part of the
+// configuration where a rule applies. An empty scope refers to the whole configuration.
+type Scope []string
+
+// Pointer returns the scope as an escaped JSON pointer (RFC 6901)
+func (s Scope) Pointer() string {
+	if len(s) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	for _, token := range s {
+		sb.WriteByte('/')
+		sb.WriteString(pointerEscaper.Replace(token))
+	}
+	return sb.String()
+}
+
+// parsePointer splits an escaped JSON pointer into its reference tokens
+func parsePointer(p string) Scope {
+	if p == "" {
+		return Scope{}
+	}
+	tokens := strings.Split(strings.TrimPrefix(p, "/"), "/")
+	for i := range tokens {
+		tokens[i] = pointerUnescaper.Replace(tokens[i])
+	}
+	return tokens
+}
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// Location points to the part of the configuration where a recommendation applies
+type Location struct {
+	Pointer  string `json:"pointer"`
+	Endpoint string `json:"endpoint,omitempty"`
+	Method   string `json:"method,omitempty"`
+	Agent    string `json:"agent,omitempty"`
+}
+
+func serviceScope(path ...string) Scope {
+	return Scope(path)
+}
+
+func endpointScope(i int, path ...string) Scope {
+	return append(Scope{"endpoints", strconv.Itoa(i)}, path...)
+}
+
+func endpointBackendScope(i, j int, path ...string) Scope {
+	return append(Scope{"endpoints", strconv.Itoa(i), "backend", strconv.Itoa(j)}, path...)
+}
+
+func agentScope(i int, path ...string) Scope {
+	return append(Scope{"async_agent", strconv.Itoa(i)}, path...)
+}
+
+func agentBackendScope(i, j int, path ...string) Scope {
+	return append(Scope{"async_agent", strconv.Itoa(i), "backend", strconv.Itoa(j)}, path...)
+}
+
+func extraConfig(namespace string, path ...string) []string {
+	return append([]string{"extra_config", namespace}, path...)
+}
+
+// newLocation resolves the scope against the source configuration, adding the
+// endpoint path and method or the name of the agent it belongs to
+func newLocation(cfg *config.ServiceConfig, s Scope) Location {
+	l := Location{Pointer: s.Pointer()}
+	if cfg == nil || len(s) < 2 {
+		return l
+	}
+	i, err := strconv.Atoi(s[1])
+	if err != nil || i < 0 {
+		return l
+	}
+	switch s[0] {
+	case "endpoints":
+		if i < len(cfg.Endpoints) && cfg.Endpoints[i] != nil {
+			l.Endpoint = cfg.Endpoints[i].Endpoint
+			l.Method = cfg.Endpoints[i].Method
+		}
+	case "async_agent":
+		if i < len(cfg.AsyncAgents) && cfg.AsyncAgents[i] != nil {
+			l.Agent = cfg.AsyncAgents[i].Name
+		}
+	}
+	return l
+}
@@ -0,0 +1,20 @@
+package audit
+
+import "testing"
+
+func TestScope_Pointer(t *testing.T) {
+	for _, tc := range []struct {
+		scope    Scope
+		expected string
+	}{
+		{scope: serviceScope(), expected: ""},
+		{scope: serviceScope("tls", "disabled"), expected: "/tls/disabled"},
+		{scope: endpointScope(3, "timeout"), expected: "/endpoints/3/timeout"},
+		{scope: endpointBackendScope(12, 0, extraConfig("qos/http-cache")...), expected: "/endpoints/12/backend/0/extra_config/qos~1http-cache"},
+		{scope: serviceScope(extraConfig("a~b")...), expected: "/extra_config/a~0b"},
+	} {
+		if p := tc.scope.Pointer(); p != tc.expected {
+			t.Errorf("unexpected pointer. have: %q, want: %q", p, tc.expected)
+		}
+	}
+}
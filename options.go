@@ -0,0 +1,203 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"time"
+)
+
+// Option customizes a single execution of the audit
+type Option func(*options)
+
+type options struct {
+	ctx         context.Context
+	minSeverity Severity
+	severities  []Severity
+	include     []string
+	exclude     []string
+	ignore      map[string]struct{}
+	sections    map[string]struct{}
+	overrides   map[string]Severity
+	now         func() time.Time
+	baseline    *AuditResult
+	weights     ScoreWeights
+}
+
+// WithContext sets the context of the audit, so it can be cancelled
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// WithMinSeverity skips the rules with a severity lower than the received one
+func WithMinSeverity(severity Severity) Option {
+	return func(o *options) {
+		o.minSeverity = severity
+	}
+}
+
+// WithSeverities skips the rules with a severity not included in the received list. An
+// empty list does not filter any rule
+func WithSeverities(severities ...Severity) Option {
+	return func(o *options) {
+		if len(severities) == 0 {
+			return
+		}
+		if o.severities == nil {
+			o.severities = []Severity{}
+		}
+		o.severities = append(o.severities, severities...)
+	}
+}
+
+// WithSeverityOverrides re-grades the rules with the ids used as keys of the received map.
+// The overridden severities are used for filtering and reporting, and the recommendations
+// keep the original severity of the rule for reference
+func WithSeverityOverrides(overrides map[string]Severity) Option {
+	return func(o *options) {
+		if o.overrides == nil {
+			o.overrides = map[string]Severity{}
+		}
+		for id, s := range overrides {
+			o.overrides[id] = s
+		}
+	}
+}
+
+// IncludeRules skips the rules with an id not matching any of the received glob patterns
+// (e.g. "7.*"). The syntax of the patterns is the one supported by path.Match
+func IncludeRules(patterns ...string) Option {
+	return func(o *options) {
+		o.include = append(o.include, patterns...)
+	}
+}
+
+// ExcludeRules skips the rules with an id matching any of the received glob patterns
+// (e.g. "3.3.*"). The syntax of the patterns is the one supported by path.Match
+func ExcludeRules(patterns ...string) Option {
+	return func(o *options) {
+		o.exclude = append(o.exclude, patterns...)
+	}
+}
+
+// WithClock sets the function returning the current time, used to discard the expired
+// suppressions
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// onlySeverities skips the rules with a severity not included in the received list, even
+// when it is empty, as the severities of Audit always did
+func onlySeverities(severities []Severity) Option {
+	return func(o *options) {
+		o.severities = severities
+	}
+}
+
+// ignoreRules skips the rules with an id equal to any of the received ones, as the ignore
+// list of Audit does not support patterns
+func ignoreRules(ids ...string) Option {
+	return func(o *options) {
+		if o.ignore == nil {
+			o.ignore = map[string]struct{}{}
+		}
+		for _, id := range ids {
+			o.ignore[id] = struct{}{}
+		}
+	}
+}
+
+// WithSections skips the rules not belonging to the received sections of the rule set
+// (e.g. "2" for the service level recommendations). An empty list does not filter any rule
+func WithSections(sections ...string) Option {
+	return func(o *options) {
+		if len(sections) == 0 {
+			return
+		}
+		if o.sections == nil {
+			o.sections = map[string]struct{}{}
+		}
+		for _, s := range sections {
+			o.sections[s] = struct{}{}
+		}
+	}
+}
+
+func newOptions(opts []Option) (*options, error) {
+	o := &options{ctx: context.Background(), now: time.Now, weights: DefaultScoreWeights()}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.ctx == nil {
+		o.ctx = context.Background()
+	}
+	if o.now == nil {
+		o.now = time.Now
+	}
+	var err error
+	if o.minSeverity != "" {
+		if o.minSeverity, err = ParseSeverity(string(o.minSeverity)); err != nil {
+			return nil, err
+		}
+	}
+	for i, s := range o.severities {
+		if o.severities[i], err = ParseSeverity(string(s)); err != nil {
+			return nil, err
+		}
+	}
+	for id, s := range o.overrides {
+		if o.overrides[id], err = ParseSeverity(string(s)); err != nil {
+			return nil, fmt.Errorf("overriding the severity of %s: %w", id, err)
+		}
+	}
+	if err := o.weights.Validate(); err != nil {
+		return nil, err
+	}
+	for _, patterns := range [][]string{o.include, o.exclude} {
+		for _, p := range patterns {
+			if _, err := path.Match(p, ""); err != nil {
+				return nil, fmt.Errorf("invalid rule pattern %q: %w", p, err)
+			}
+		}
+	}
+	return o, nil
+}
+
+// selects reports if the rule with the received id should be evaluated, ignoring its severity
+func (o *options) selects(id string) bool {
+	if o.sections != nil {
+		if _, ok := o.sections[ruleSection(id)]; !ok {
+			return false
+		}
+	}
+	if _, ok := o.ignore[id]; ok {
+		return false
+	}
+	if len(o.include) > 0 && !matchesAny(o.include, id) {
+		return false
+	}
+	return !matchesAny(o.exclude, id)
+}
+
+// catches reports if the rules with the received severity should be evaluated
+func (o *options) catches(severity Severity) bool {
+	if o.severities != nil && !slices.Contains(o.severities, severity) {
+		return false
+	}
+	return o.minSeverity == "" || severity.AtLeast(o.minSeverity)
+}
+
+func matchesAny(patterns []string, id string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, id); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/luraproject/lura/v2/config"
+)
+
+func TestRun(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		opts     []Option
+		expected []string
+	}{
+		{
+			name: "threshold, sections and exclusions",
+			opts: []Option{
+				WithMinSeverity(SeverityHigh),
+				WithSections("2", "3"),
+				ExcludeRules("3.3.*"),
+			},
+			expected: []string{"2.1.3", "2.1.7", "2.1.8", "2.2.2", "2.2.3", "2.2.4", "3.1.3"},
+		},
+		{
+			name:     "inclusions",
+			opts:     []Option{IncludeRules("7.*", "1.1.1")},
+			expected: []string{"1.1.1", "7.1.3", "7.1.7", "7.2.4", "7.3.1"},
+		},
+		{
+			name:     "inclusions and severities",
+			opts:     []Option{IncludeRules("7.*"), WithSeverities(SeverityMedium)},
+			expected: []string{"7.3.1"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := loadExample1(t)
+			result, err := Run(cfg, tc.opts...)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if len(result.Recommendations) != len(tc.expected) {
+				t.Errorf("wrong number of recommendations. have %d, expected %d", len(result.Recommendations), len(tc.expected))
+				return
+			}
+			for i, id := range tc.expected {
+				if result.Recommendations[i].Rule != id {
+					t.Errorf("unexpected rule %d: %s", i, result.Recommendations[i].Rule)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_emptyFilters(t *testing.T) {
+	cfg := loadExample1(t)
+	all, err := Run(cfg)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	result, err := Run(cfg, WithSeverities(), WithSections())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(result.Recommendations) == 0 || len(result.Recommendations) != len(all.Recommendations) {
+		t.Errorf("unexpected number of recommendations. have %d, expected %d", len(result.Recommendations), len(all.Recommendations))
+	}
+
+	result, err = Audit(cfg, nil, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(result.Recommendations) != 0 {
+		t.Errorf("the legacy audit without severities returned %d recommendations", len(result.Recommendations))
+	}
+}
+
+func TestRun_errors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cfg := loadExample1(t)
+	if _, err := Run(cfg, WithContext(ctx)); !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := Run(cfg, WithMinSeverity("SEVERE")); !errors.Is(err, ErrInvalidSeverity) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := Run(cfg, WithSeverities(SeverityHigh, "CRTICAL")); !errors.Is(err, ErrInvalidSeverity) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := Run(cfg, ExcludeRules("[")); err == nil {
+		t.Error("expecting an error with a malformed pattern")
+	}
+}
+
+func loadExample1(t *testing.T) *config.ServiceConfig {
+	t.Helper()
+	cfg, err := config.NewParser().Parse("./tests/example1.json")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	cfg.Normalize()
+	return &cfg
+}
+
+func TestRun_severityOverrides(t *testing.T) {
+	cfg := loadExample1(t)
+	result, err := Run(
+		cfg,
+		WithSections("2"),
+		WithMinSeverity(SeverityHigh),
+		WithSeverityOverrides(map[string]Severity{
+			"2.2.2": SeverityLow,
+			"2.3.1": "critical",
+			"2.1.7": SeverityHigh,
+		}),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := []Recommendation{
+		{Rule: "2.1.3", Severity: SeverityCritical},
+		{Rule: "2.1.7", Severity: SeverityHigh},
+		{Rule: "2.1.8", Severity: SeverityHigh},
+		{Rule: "2.2.3", Severity: SeverityHigh},
+		{Rule: "2.2.4", Severity: SeverityHigh},
+		{Rule: "2.3.1", Severity: SeverityCritical, OriginalSeverity: SeverityMedium},
+	}
+	if len(result.Recommendations) != len(expected) {
+		t.Errorf("wrong number of recommendations. have %d, expected %d", len(result.Recommendations), len(expected))
+		return
+	}
+	for i, r := range result.Recommendations {
+		if r.Rule != expected[i].Rule || r.Severity != expected[i].Severity || r.OriginalSeverity != expected[i].OriginalSeverity {
+			t.Errorf("unexpected recommendation %d: %+v", i, r)
+		}
+	}
+
+	if _, err := Run(cfg, WithSeverityOverrides(map[string]Severity{"99.1.1": SeverityLow})); !errors.Is(err, ErrUnknownRule) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := Run(cfg, WithSeverityOverrides(map[string]Severity{"2.2.2": "NONE"})); !errors.Is(err, ErrInvalidSeverity) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -1,7 +1,14 @@
 package audit
 
 import (
+	"crypto/tls"
 	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,6 +20,7 @@ import (
 	luaproxy "github.com/krakend/krakend-lua/v2/proxy"
 	luarouter "github.com/krakend/krakend-lua/v2/router"
 	opencensus "github.com/krakend/krakend-opencensus/v2"
+	ratelimitProxy "github.com/krakend/krakend-ratelimit/v3/proxy"
 	ratelimit "github.com/krakend/krakend-ratelimit/v3/router"
 	rss "github.com/krakend/krakend-rss/v2"
 	xml "github.com/krakend/krakend-xml/v2"
@@ -46,6 +54,9 @@ func Parse(cfg *config.ServiceConfig) Service {
 		// connections inside the client_tls config:
 		v1 = addBit(v1, ServiceAllowInsecureConnections)
 	}
+	if cfg.ClientTLS != nil && cfg.ClientTLS.AllowInsecureConnections {
+		v1 = addBit(v1, ServiceClientTLSAllowInsecureConnections)
+	}
 
 	if cfg.DisableStrictREST {
 		v1 = addBit(v1, ServiceDisableStrictREST)
@@ -78,15 +89,40 @@ func Parse(cfg *config.ServiceConfig) Service {
 		v1 = addBit(v1, ServiceUseH2C)
 	}
 
+	details := make([]int, ServiceDetailMaxHeaderBytes+1)
+	details[ServiceDetailFlags] = v1
+	details[ServiceDetailReadTimeout] = int(cfg.ReadTimeout / time.Millisecond)
+	details[ServiceDetailWriteTimeout] = int(cfg.WriteTimeout / time.Millisecond)
+	details[ServiceDetailIdleTimeout] = int(cfg.IdleTimeout / time.Millisecond)
+	details[ServiceDetailReadHeaderTimeout] = int(cfg.ReadHeaderTimeout / time.Millisecond)
+	details[ServiceDetailMaxHeaderBytes] = cfg.MaxHeaderBytes
+	if cfg.TLS != nil {
+		details[ServiceDetailTLSMinVersion] = parseTLSVersion(cfg.TLS.MinVersion)
+		details[ServiceDetailTLSMaxVersion] = parseTLSVersion(cfg.TLS.MaxVersion)
+		details[ServiceDetailTLSCipherSuites] = parseCipherSuites(cfg.TLS.CipherSuites)
+		details[ServiceDetailTLSCurves] = len(cfg.TLS.CurvePreferences)
+		details[ServiceDetailTLSKeys] = len(cfg.TLS.Keys)
+		if cfg.TLS.PublicKey != "" || cfg.TLS.PrivateKey != "" {
+			details[ServiceDetailTLSKeys]++
+		}
+	}
+	if cfg.ClientTLS != nil {
+		details[ServiceDetailClientTLSMinVersion] = parseTLSVersion(cfg.ClientTLS.MinVersion)
+		details[ServiceDetailClientTLSMaxVersion] = parseTLSVersion(cfg.ClientTLS.MaxVersion)
+		details[ServiceDetailClientTLSCipherSuites] = parseCipherSuites(cfg.ClientTLS.CipherSuites)
+		details[ServiceDetailClientTLSCurves] = len(cfg.ClientTLS.CurvePreferences)
+		details[ServiceDetailClientTLSCerts] = len(cfg.ClientTLS.ClientCerts)
+	}
+
 	return Service{
-		Details:    []int{v1},
-		Agents:     parseAsyncAgents(cfg.AsyncAgents),
-		Endpoints:  parseEndpoints(cfg.Endpoints),
+		Details:    details,
+		Agents:     parseAsyncAgents(cfg.AsyncAgents, cfg.Host),
+		Endpoints:  parseEndpoints(cfg.Endpoints, cfg.Host),
 		Components: parseComponents(cfg.ExtraConfig),
 	}
 }
 
-func parseAsyncAgents(as []*config.AsyncAgent) []Agent {
+func parseAsyncAgents(as []*config.AsyncAgent, hosts []string) []Agent {
 	var agents []Agent
 
 	for _, a := range as {
@@ -97,7 +133,7 @@ func parseAsyncAgents(as []*config.AsyncAgent) []Agent {
 				a.Connection.MaxRetries,
 				int(a.Consumer.Timeout / time.Millisecond),
 			},
-			Backends:   parseBackends(a.Backend),
+			Backends:   parseBackends(a.Backend, hosts),
 			Components: parseComponents(a.ExtraConfig),
 		}
 
@@ -113,8 +149,54 @@ const (
 	BitEndpointCatchAll             int = 3
 )
 
-func parseEndpoints(es []*config.EndpointConfig) []Endpoint {
+// Positions of the endpoint details. The segments of the route start at
+// EndpointDetailRoute and take the rest of the details
+const (
+	EndpointDetailEncoding = iota
+	EndpointDetailQueryString
+	EndpointDetailHeadersToPass
+	EndpointDetailTimeout
+	EndpointDetailWildcards
+	EndpointDetailUnsafeMethods
+	EndpointDetailMethod
+	EndpointDetailCacheTTL
+	EndpointDetailCredentialHeaders
+	EndpointDetailRoute
+)
+
+// Bits of the credential headers forwarded by the endpoint. The header wildcard
+// forwards all of them
+const (
+	CredentialHeaderAuthorization = iota
+	CredentialHeaderCookie
+)
+
+// Kinds of the route segments, stored in the lowest bits of each segment. The rest of
+// the bits hold the identifier of the literal or the parameter name, assigned in order of
+// appearance while parsing the endpoints. Equal names share the same identifier, so the
+// routes are compared by value and no path is kept
+const (
+	RouteSegmentLiteral = iota
+	RouteSegmentParam
+	RouteSegmentWildcard
+)
+
+const routeSegmentKindBits = 2
+
+const (
+	MethodGET = iota
+	MethodPOST
+	MethodPUT
+	MethodPATCH
+	MethodDELETE
+	MethodHEAD
+	MethodOPTIONS
+	MethodOther
+)
+
+func parseEndpoints(es []*config.EndpointConfig, hosts []string) []Endpoint {
 	var endpoints []Endpoint
+	routeNames := routeSegmentNames{}
 
 	for _, e := range es {
 		wildcards := 0
@@ -132,10 +214,16 @@ func parseEndpoints(es []*config.EndpointConfig) []Endpoint {
 				break
 			}
 		}
+		credentialHeaders := 0
 		for _, s := range e.HeadersToPass {
-			if s == "*" {
+			switch strings.ToLower(s) {
+			case "*":
 				wildcards = wildcards | 4
-				break
+				credentialHeaders = addBit(addBit(credentialHeaders, CredentialHeaderAuthorization), CredentialHeaderCookie)
+			case "authorization":
+				credentialHeaders = addBit(credentialHeaders, CredentialHeaderAuthorization)
+			case "cookie":
+				credentialHeaders = addBit(credentialHeaders, CredentialHeaderCookie)
 			}
 		}
 
@@ -160,16 +248,88 @@ func parseEndpoints(es []*config.EndpointConfig) []Endpoint {
 				int(e.Timeout / time.Millisecond),
 				wildcards,
 				numUnsafeMethods,
+				parseMethod(e.Method),
+				int(e.CacheTTL / time.Millisecond),
+				credentialHeaders,
 			},
-			Backends:   parseBackends(e.Backend),
+			Backends:   parseBackends(e.Backend, hosts),
 			Components: parseComponents(e.ExtraConfig),
 		}
 
+		endpoint.Details = append(endpoint.Details, parseRoute(e.Endpoint, routeNames)...)
+
 		endpoints = append(endpoints, endpoint)
 	}
 	return endpoints
 }
 
+const ratelimitServiceNamespace = "qos/ratelimit/service"
+
+// ratePerHour converts the rates per second of the rate limits, already normalized with
+// their every option, to requests per hour, so slow rates are not rounded to zero
+func ratePerHour(rate float64) int {
+	return int(math.Round(rate * 3600))
+}
+
+func parseMethod(method string) int {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, "":
+		return addBit(0, MethodGET)
+	case http.MethodPost:
+		return addBit(0, MethodPOST)
+	case http.MethodPut:
+		return addBit(0, MethodPUT)
+	case http.MethodPatch:
+		return addBit(0, MethodPATCH)
+	case http.MethodDelete:
+		return addBit(0, MethodDELETE)
+	case http.MethodHead:
+		return addBit(0, MethodHEAD)
+	case http.MethodOptions:
+		return addBit(0, MethodOPTIONS)
+	}
+	return addBit(0, MethodOther)
+}
+
+// routeSegmentNames holds the identifiers of the literals and parameter names of the routes
+type routeSegmentNames map[routeSegmentName]int
+
+type routeSegmentName struct {
+	kind int
+	name string
+}
+
+// parseRoute normalizes the path of the endpoint into its segments. The {param} and :param
+// notations are both parameters, and the segments ending with * are wildcards
+func parseRoute(path string, names routeSegmentNames) []int {
+	var segments []int
+	for _, segment := range strings.Split(path, "/") {
+		switch {
+		case segment == "":
+			continue
+		case strings.HasSuffix(segment, "*"):
+			segments = append(segments, RouteSegmentWildcard)
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			segments = append(segments, names.segment(RouteSegmentParam, segment[1:len(segment)-1]))
+		case strings.HasPrefix(segment, ":"):
+			segments = append(segments, names.segment(RouteSegmentParam, segment[1:]))
+		default:
+			segments = append(segments, names.segment(RouteSegmentLiteral, segment))
+		}
+	}
+	return segments
+}
+
+func (r routeSegmentNames) segment(kind int, name string) int {
+	key := routeSegmentName{kind: kind, name: name}
+	id, ok := r[key]
+	if !ok {
+		id = len(r) + 1
+		r[key] = id
+	}
+	return id<<routeSegmentKindBits | kind
+}
+
 func parseEncoding(enc string) int {
 	switch enc {
 	case encoding.NOOP:
@@ -189,7 +349,9 @@ func parseEncoding(enc string) int {
 	}
 }
 
-func parseBackends(bs []*config.Backend) []Backend {
+// parseBackends summarizes the backends. Like lura, the backends without hosts fall back
+// to the hosts of the service
+func parseBackends(bs []*config.Backend, hosts []string) []Backend {
 	var backends []Backend
 
 	for _, b := range bs {
@@ -212,8 +374,12 @@ func parseBackends(bs []*config.Backend) []Backend {
 		if b.IsCollection {
 			v1 = addBit(v1, BackendIsCollection)
 		}
+		bHosts := b.Host
+		if len(bHosts) == 0 {
+			bHosts = hosts
+		}
 		backend := Backend{
-			Details:    []int{v1},
+			Details:    []int{v1, parseHosts(bHosts), parseSD(b.SD), len(bHosts)},
 			Components: parseComponents(b.ExtraConfig),
 		}
 
@@ -226,6 +392,9 @@ func parseComponents(cfg config.ExtraConfig) Component { // skipcq: GO-R1005
 	components := Component{}
 	for c, v := range cfg {
 		switch c {
+		case SuppressionNamespace:
+			// audit annotations are not components of the gateway
+			continue
 		case server.Namespace:
 			cfg, ok := v.(map[string]interface{})
 			if !ok {
@@ -322,7 +491,7 @@ func parseComponents(cfg config.ExtraConfig) Component { // skipcq: GO-R1005
 				continue
 			}
 
-			res := make([]int, 4)
+			res := make([]int, 5)
 			if ks, ok := cfg["allow"].([]interface{}); ok {
 				res[0] = len(ks)
 			}
@@ -335,6 +504,9 @@ func parseComponents(cfg config.ExtraConfig) Component { // skipcq: GO-R1005
 			if s, ok := cfg["cache_size"].(float64); ok {
 				res[3] = int(s)
 			}
+			if b, ok := cfg["empty_user_agent_is_bot"].(bool); ok && b {
+				res[4] = 1
+			}
 			components[c] = res
 
 		case opencensus.Namespace:
@@ -379,29 +551,48 @@ func parseComponents(cfg config.ExtraConfig) Component { // skipcq: GO-R1005
 
 			components[c] = []int{v1}
 
-		case ratelimit.Namespace:
-			cfg, ok := v.(map[string]interface{})
-			if !ok {
+		case ratelimit.Namespace, ratelimitServiceNamespace:
+			// the service limits share the options of the router ones
+			cfg, err := ratelimit.ConfigGetter(config.ExtraConfig{ratelimit.Namespace: v})
+			if err != nil {
 				continue
 			}
 
 			v1 := 0
-			if vs, ok := cfg["max_rate"].(float64); ok && vs > 0 {
-				v1 = 1
+			if cfg.MaxRate > 0 {
+				v1 = addBit(v1, RatelimitMaxRate)
 			}
-			if vs, ok := cfg["client_max_rate"].(float64); ok && vs > 0 {
-				v1 += 2
+			if cfg.ClientMaxRate > 0 {
+				v1 = addBit(v1, RatelimitClientMaxRate)
 			}
-			if vs, ok := cfg["strategy"].(string); ok {
-				switch vs {
-				case "ip":
-					v1 += 4
-				case "header":
-					v1 += 8
-				}
+			switch strings.ToLower(cfg.Strategy) {
+			case "":
+			case "ip":
+				v1 = addBit(v1, RatelimitStrategyIP)
+			case "header":
+				v1 = addBit(v1, RatelimitStrategyHeader)
+			case "param":
+				v1 = addBit(v1, RatelimitStrategyParam)
+			default:
+				v1 = addBit(v1, RatelimitStrategyUnknown)
+			}
+			if cfg.Key != "" {
+				v1 = addBit(v1, RatelimitKey)
 			}
 
-			components[c] = []int{v1}
+			components[c] = []int{
+				v1,
+				ratePerHour(cfg.MaxRate),
+				int(cfg.Capacity),
+				ratePerHour(cfg.ClientMaxRate),
+				int(cfg.ClientCapacity),
+			}
+		case ratelimitProxy.Namespace:
+			cfg, err := ratelimitProxy.ConfigGetter(config.ExtraConfig{ratelimitProxy.Namespace: v})
+			if err != nil {
+				continue
+			}
+			components[c] = []int{ratePerHour(cfg.MaxRate), int(cfg.Capacity)}
 		case "backend/http/client":
 			cfg, ok := v.(map[string]interface{})
 			if !ok {
@@ -409,10 +600,13 @@ func parseComponents(cfg config.ExtraConfig) Component { // skipcq: GO-R1005
 				continue
 			}
 			v1 := 1
+			minVersion, cipherSuites := 0, 0
 			if clientTLS, ok := cfg["client_tls"].(map[string]interface{}); ok {
 				var cTLS config.ClientTLS
 				err := mapstructure.Decode(clientTLS, &cTLS)
 				if err == nil {
+					minVersion = parseTLSVersion(cTLS.MinVersion)
+					cipherSuites = parseCipherSuites(cTLS.CipherSuites)
 					if cTLS.AllowInsecureConnections {
 						v1 = addBit(v1, BackendComponentHTTPClientAllowInsecureConnections)
 					}
@@ -423,7 +617,7 @@ func parseComponents(cfg config.ExtraConfig) Component { // skipcq: GO-R1005
 					}
 				}
 			}
-			components[c] = []int{v1}
+			components[c] = []int{v1, minVersion, cipherSuites}
 		case "telemetry/moesif":
 			cfg, ok := v.(map[string]interface{})
 			if !ok {
@@ -453,10 +647,13 @@ func parseComponents(cfg config.ExtraConfig) Component { // skipcq: GO-R1005
 			numOTLPMetrics := 0
 			numOTLPTraces := 0
 			numPrometheus := 0
+			numExporters := 0
+			insecureOTLP := 0
 			if exporters, ok := cfg["exporters"].(map[string]interface{}); ok {
 				if prom, ok := exporters["prometheus"].([]interface{}); ok {
 					for _, p := range prom {
 						if po, ok := p.(map[string]interface{}); ok {
+							numExporters++
 							if b, ok := po["disable_metrics"].(bool); !ok || !b {
 								numPrometheus += 1
 							}
@@ -464,14 +661,20 @@ func parseComponents(cfg config.ExtraConfig) Component { // skipcq: GO-R1005
 					}
 				}
 				if otlp, ok := exporters["otlp"].([]interface{}); ok {
-					for _, o := range otlp {
+					for i, o := range otlp {
 						if oo, ok := o.(map[string]interface{}); ok {
+							numExporters++
 							if b, ok := oo["disable_metrics"].(bool); !ok || !b {
 								numOTLPMetrics += 1
 							}
 							if b, ok := oo["disable_traces"].(bool); !ok || !b {
 								numOTLPTraces += 1
 							}
+							// the hosts are classified like the backend ones, and only the first exporters
+							// fit in the bitset of positions
+							if h, ok := oo["host"].(string); ok && i < 31 && hasBit(parseHosts([]string{h}), BackendHostPlainRemote) {
+								insecureOTLP = addBit(insecureOTLP, i)
+							}
 						}
 					}
 				}
@@ -482,6 +685,8 @@ func parseComponents(cfg config.ExtraConfig) Component { // skipcq: GO-R1005
 				numOTLPMetrics,         // to check if we do not have metrics
 				numOTLPTraces,          // to check if we do not have traces
 				numPrometheus,          // to check if we do not have metrics
+				numExporters,           // to check if all the exporters are disabled
+				insecureOTLP,           // positions of the otlp exporters using clear text
 			}
 		case "grpc":
 			cfg, ok := v.(map[string]interface{})
@@ -503,7 +708,7 @@ func parseComponents(cfg config.ExtraConfig) Component { // skipcq: GO-R1005
 				}
 			}
 
-		case "validation/response-json-schema":
+		case responseSchemaNamespace:
 			cfg, ok := v.(map[string]interface{})
 			if !ok {
 				components[c] = []int{}
@@ -531,31 +736,25 @@ func parseComponents(cfg config.ExtraConfig) Component { // skipcq: GO-R1005
 				}
 			}
 			components[c] = p
-		case "modifier/response-body":
+		case responseBodyModifierNamespace:
 			cfg, ok := v.(map[string]interface{})
 			if !ok {
 				components[c] = []int{}
 				continue
 			}
-			p := make([]int, 6)
+			p := make([]int, len(responseBodyModifierKinds)+1)
 			modifiers, ok := cfg["modifiers"].([]interface{})
 			if ok {
 				p[0] = len(modifiers)
-				for i := range modifiers {
-					var kind string
-					for kind = range modifiers[i].(map[string]interface{}) {
+				for _, m := range modifiers {
+					modifier, ok := m.(map[string]interface{})
+					if !ok {
+						continue
 					}
-					switch kind {
-					case "regexp":
-						p[1]++
-					case "literal":
-						p[2]++
-					case "upper":
-						p[3]++
-					case "lower":
-						p[4]++
-					case "trim":
-						p[5]++
+					for kind := range modifier {
+						if k := slices.Index(responseBodyModifierKinds, kind); k >= 0 {
+							p[k+1]++
+						}
 					}
 				}
 			}
@@ -655,13 +854,26 @@ func parseComponents(cfg config.ExtraConfig) Component { // skipcq: GO-R1005
 				continue
 			}
 			f := 0
-			if _, ok := cfg["pre"].(string); ok {
-				f = addBit(f, 0)
+			inlineSize := 0
+			if code, ok := cfg["pre"].(string); ok {
+				f = addBit(f, LuaPre)
+				inlineSize += len(code)
 			}
-			if _, ok := cfg["post"].(string); ok {
-				f = addBit(f, 1)
+			if code, ok := cfg["post"].(string); ok {
+				f = addBit(f, LuaPost)
+				inlineSize += len(code)
 			}
-			components[c] = []int{f}
+			if b, ok := cfg["allow_open_libs"].(bool); ok && b {
+				f = addBit(f, LuaAllowOpenLibs)
+			}
+			if b, ok := cfg["live"].(bool); ok && b {
+				f = addBit(f, LuaLive)
+			}
+			numSources := 0
+			if sources, ok := cfg["sources"].([]interface{}); ok {
+				numSources = len(sources)
+			}
+			components[c] = []int{f, numSources, inlineSize}
 		case httpcache.Namespace:
 			cfg, ok := v.(map[string]interface{})
 			if !ok {
@@ -670,13 +882,13 @@ func parseComponents(cfg config.ExtraConfig) Component { // skipcq: GO-R1005
 			}
 			f := 0
 			if e, ok := cfg["shared"].(bool); ok && e {
-				f = addBit(f, 0)
+				f = addBit(f, HTTPCacheShared)
 			}
 			if m, ok := cfg["max_items"].(float64); ok && m > 0 {
-				f = addBit(f, 1)
+				f = addBit(f, HTTPCacheMaxItems)
 			}
 			if m, ok := cfg["max_size"].(float64); ok && m > 0 {
-				f = addBit(f, 2)
+				f = addBit(f, HTTPCacheMaxSize)
 			}
 			components[c] = []int{f}
 		case "ai/mcp":
@@ -693,8 +905,10 @@ func parseComponents(cfg config.ExtraConfig) Component { // skipcq: GO-R1005
 
 			numServers := 0
 			numTools := 0
+			maxTools := 0
 			servers, serversFound := cfg["servers"].([]interface{})
 			if !serversFound {
+				components[c] = []int{0, 0, 0}
 				continue
 			}
 
@@ -707,9 +921,12 @@ func parseComponents(cfg config.ExtraConfig) Component { // skipcq: GO-R1005
 
 				if tools, ok := server["tools"].([]interface{}); ok {
 					numTools += len(tools)
+					if len(tools) > maxTools {
+						maxTools = len(tools)
+					}
 				}
 			}
-			components[c] = []int{numServers, numTools}
+			components[c] = []int{numServers, numTools, maxTools}
 		case "ai/llm":
 			cfg, ok := v.(map[string]interface{})
 			if !ok {
@@ -717,6 +934,8 @@ func parseComponents(cfg config.ExtraConfig) Component { // skipcq: GO-R1005
 				continue
 			}
 
+			// the unknown providers keep the presence of the component
+			components[c] = []int{0, 0, 0, 0, 0}
 			for i, pr := range AiProviders {
 				p := 0
 				customInput := 0
@@ -724,6 +943,14 @@ func parseComponents(cfg config.ExtraConfig) Component { // skipcq: GO-R1005
 				if prCfg, providerFound := cfg[pr[0]].(map[string]interface{}); providerFound {
 					providerVersions := pr[1:]
 					vp := 0
+					// the provider is known but some of its versions are older than the
+					// supported ones
+					outdated := 0
+					for k := range prCfg {
+						if isOutdatedAPIVersion(k, providerVersions) {
+							outdated = addBit(outdated, i)
+						}
+					}
 					for vi, v := range providerVersions {
 						vCfg, versionFound := prCfg[v].(map[string]interface{})
 						if !versionFound {
@@ -739,7 +966,7 @@ func parseComponents(cfg config.ExtraConfig) Component { // skipcq: GO-R1005
 						}
 					}
 
-					components[c] = []int{p, customInput, customOutput, vp}
+					components[c] = []int{p, customInput, customOutput, vp, outdated}
 					break
 				}
 			}
@@ -750,6 +977,57 @@ func parseComponents(cfg config.ExtraConfig) Component { // skipcq: GO-R1005
 	return components
 }
 
+// isAPIVersion checks if the key looks like the version of a provider API, such as v1,
+// v2 or v1beta
+func isAPIVersion(k string) bool {
+	_, ok := parseAPIVersion(k)
+	return ok
+}
+
+// parseAPIVersion returns the major version, the stability (alpha, beta or stable) and
+// the revision of the stability of an API version, so they can be compared in order
+func parseAPIVersion(k string) ([3]int, bool) {
+	if len(k) < 2 || k[0] != 'v' || k[1] < '0' || k[1] > '9' {
+		return [3]int{}, false
+	}
+	rest := strings.TrimLeft(k[1:], "0123456789")
+	major, err := strconv.Atoi(k[1 : len(k)-len(rest)])
+	if err != nil {
+		return [3]int{}, false
+	}
+	if rest == "" {
+		return [3]int{major, 2, 0}, true
+	}
+	for stability, prefix := range []string{"alpha", "beta"} {
+		if !strings.HasPrefix(rest, prefix) {
+			continue
+		}
+		revision := 0
+		if r := rest[len(prefix):]; r != "" {
+			if revision, err = strconv.Atoi(r); err != nil {
+				return [3]int{}, false
+			}
+		}
+		return [3]int{major, stability, revision}, true
+	}
+	return [3]int{}, false
+}
+
+// isOutdatedAPIVersion checks if the key is an API version older than all the supported
+// ones. The unknown newer versions are not outdated
+func isOutdatedAPIVersion(k string, supported []string) bool {
+	v, ok := parseAPIVersion(k)
+	if !ok || slices.Contains(supported, k) {
+		return false
+	}
+	for _, s := range supported {
+		if sv, ok := parseAPIVersion(s); ok && slices.Compare(v[:], sv[:]) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
 func parseRouter(cfg config.ExtraConfig) int {
 	res := 0
 	v, ok := cfg["error_body"].(bool)
@@ -848,6 +1126,115 @@ func parseRouter(cfg config.ExtraConfig) int {
 	return res
 }
 
+// parseHosts returns a bitset with the schemes and the kind of addresses of the hosts,
+// so no host name or address is kept. Hosts without scheme default to http, as in lura.
+// The clear text hosts outside the loopback interface and the private ranges are
+// flagged as plain remote hosts
+func parseHosts(hosts []string) int {
+	v := 0
+	for _, h := range hosts {
+		if !strings.Contains(h, "://") {
+			h = "http://" + h
+		}
+		u, err := url.Parse(h)
+		if err != nil {
+			v = addBit(v, BackendHostOtherScheme)
+			continue
+		}
+		kind := parseHostKind(u.Hostname())
+		v |= kind
+		switch strings.ToLower(u.Scheme) {
+		case "http":
+			v = addBit(v, BackendHostHTTP)
+			if !hasBit(kind, BackendHostLoopback) && !hasBit(kind, BackendHostPrivate) {
+				v = addBit(v, BackendHostPlainRemote)
+			}
+		case "https":
+			v = addBit(v, BackendHostHTTPS)
+		default:
+			v = addBit(v, BackendHostOtherScheme)
+		}
+	}
+	return v
+}
+
+// parseHostKind returns the bits describing the kind of address of a host name
+func parseHostKind(name string) int {
+	if name == "localhost" {
+		return addBit(0, BackendHostLoopback)
+	}
+	ip := net.ParseIP(name)
+	if ip == nil {
+		return addBit(0, BackendHostName)
+	}
+	v := addBit(0, BackendHostIP)
+	switch {
+	case ip.IsLoopback():
+		return addBit(v, BackendHostLoopback)
+	case ip.IsPrivate(), ip.IsLinkLocalUnicast():
+		return addBit(v, BackendHostPrivate)
+	}
+	return addBit(v, BackendHostPublic)
+}
+
+func parseSD(sd string) int {
+	switch sd {
+	case "", "static":
+		return BackendSDStatic
+	case "dns":
+		return BackendSDDNS
+	}
+	return BackendSDOther
+}
+
+// parseTLSVersion returns the TLS version applied by the gateway for the declared value.
+// Like the lura transport, unknown or empty values fall back to TLS 1.3
+func parseTLSVersion(v string) int {
+	switch v {
+	case "SSL3.0":
+		return tls.VersionSSL30
+	case "TLS10":
+		return tls.VersionTLS10
+	case "TLS11":
+		return tls.VersionTLS11
+	case "TLS12":
+		return tls.VersionTLS12
+	default:
+		return tls.VersionTLS13
+	}
+}
+
+// parseCipherSuites returns a bitset describing the declared cipher suites. Suites not
+// implemented by the standard library are considered insecure
+func parseCipherSuites(ids []uint16) int {
+	if len(ids) == 0 {
+		return 0
+	}
+	res := addBit(0, TLSCipherSuitesCustom)
+	insecure := map[uint16]bool{}
+	for _, cs := range tls.InsecureCipherSuites() {
+		insecure[cs.ID] = true
+	}
+	secure := map[uint16]bool{}
+	for _, cs := range tls.CipherSuites() {
+		secure[cs.ID] = true
+	}
+
+	cbcOnly := true
+	for _, id := range ids {
+		if insecure[id] || !secure[id] {
+			res = addBit(res, TLSCipherSuitesInsecure)
+		}
+		if !strings.Contains(tls.CipherSuiteName(id), "_CBC_") {
+			cbcOnly = false
+		}
+	}
+	if cbcOnly {
+		res = addBit(res, TLSCipherSuitesCBCOnly)
+	}
+	return res
+}
+
 func parseProxy(cfg config.ExtraConfig) int {
 	res := 0
 	v, ok := cfg["sequential"].(bool)
@@ -912,6 +1299,12 @@ func parseClientPlugin(name string) int {
 	return 0
 }
 
+const responseBodyModifierNamespace = "modifier/response-body"
+
+// responseBodyModifierKinds lists the modifiers of modifier/response-body in the order
+// their counters are stored, right after the total number of modifiers
+var responseBodyModifierKinds = []string{"regexp", "literal", "upper", "lower", "trim"}
+
 func parseRespReqPlugin(name string) int {
 	switch name {
 	case "response-schema-validator":
@@ -22,7 +22,22 @@ func ExampleParse() {
 	// output:
 	// {
 	//   "d": [
-	//     7220
+	//     7220,
+	//     772,
+	//     772,
+	//     0,
+	//     0,
+	//     1,
+	//     0,
+	//     0,
+	//     0,
+	//     0,
+	//     0,
+	//     0,
+	//     0,
+	//     0,
+	//     0,
+	//     0
 	//   ],
 	//   "a": null,
 	//   "e": [
@@ -33,27 +48,39 @@ func ExampleParse() {
 	//         0,
 	//         140000,
 	//         0,
-	//         0
+	//         0,
+	//         1,
+	//         0,
+	//         0,
+	//         4,
+	//         8
 	//       ],
 	//       "b": [
 	//         {
 	//           "d": [
-	//             64
+	//             64,
+	//             0,
+	//             0,
+	//             0
 	//           ],
 	//           "c": {
 	//             "github.com/devopsfaith/krakend-httpcache": [
 	//               0
 	//             ],
 	//             "github.com/devopsfaith/krakend-lua/proxy/backend": [
-	//               2
+	//               2,
+	//               0,
+	//               15
 	//             ]
 	//           }
 	//         }
 	//       ],
 	//       "c": {
-	//         "github.com/devopsfaith/krakend-jose/validator": [],
+	//         "auth/validator": [],
 	//         "github.com/devopsfaith/krakend-lua/proxy": [
-	//           3
+	//           3,
+	//           0,
+	//           35
 	//         ],
 	//         "modifier/response-body": [
 	//           5,
@@ -78,16 +105,27 @@ func ExampleParse() {
 	//         1,
 	//         10000,
 	//         7,
-	//         0
+	//         0,
+	//         1,
+	//         0,
+	//         3,
+	//         12,
+	//         8,
+	//         2
 	//       ],
 	//       "b": [
 	//         {
 	//           "d": [
-	//             64
+	//             64,
+	//             0,
+	//             0,
+	//             0
 	//           ],
 	//           "c": {
 	//             "backend/http/client": [
-	//               3
+	//               3,
+	//               772,
+	//               0
 	//             ]
 	//           }
 	//         }
@@ -105,12 +143,19 @@ func ExampleParse() {
 	//         0,
 	//         2000,
 	//         0,
-	//         0
+	//         0,
+	//         1,
+	//         0,
+	//         0,
+	//         16
 	//       ],
 	//       "b": [
 	//         {
 	//           "d": [
-	//             64
+	//             64,
+	//             0,
+	//             0,
+	//             0
 	//           ],
 	//           "c": {}
 	//         }
@@ -138,12 +183,19 @@ func ExampleParse() {
 	//         0,
 	//         2000,
 	//         0,
-	//         0
+	//         0,
+	//         1,
+	//         0,
+	//         0,
+	//         20
 	//       ],
 	//       "b": [
 	//         {
 	//           "d": [
-	//             64
+	//             64,
+	//             0,
+	//             0,
+	//             0
 	//           ],
 	//           "c": {
 	//             "github.com/devopsfaith/krakend-httpcache": [
@@ -161,19 +213,27 @@ func ExampleParse() {
 	//         0,
 	//         2000,
 	//         0,
-	//         1
+	//         1,
+	//         2,
+	//         0,
+	//         0,
+	//         24
 	//       ],
 	//       "b": [
 	//         {
 	//           "d": [
-	//             64
+	//             64,
+	//             18,
+	//             0,
+	//             1
 	//           ],
 	//           "c": {
 	//             "ai/llm": [
 	//               1,
 	//               0,
 	//               0,
-	//               1
+	//               1,
+	//               0
 	//             ]
 	//           }
 	//         }
@@ -187,19 +247,27 @@ func ExampleParse() {
 	//         0,
 	//         2000,
 	//         0,
-	//         1
+	//         1,
+	//         2,
+	//         0,
+	//         0,
+	//         28
 	//       ],
 	//       "b": [
 	//         {
 	//           "d": [
-	//             64
+	//             64,
+	//             18,
+	//             0,
+	//             1
 	//           ],
 	//           "c": {
 	//             "ai/llm": [
 	//               2,
 	//               1,
 	//               0,
-	//               1
+	//               1,
+	//               0
 	//             ]
 	//           }
 	//         }
@@ -213,19 +281,27 @@ func ExampleParse() {
 	//         0,
 	//         2000,
 	//         0,
-	//         1
+	//         1,
+	//         2,
+	//         0,
+	//         0,
+	//         32
 	//       ],
 	//       "b": [
 	//         {
 	//           "d": [
-	//             64
+	//             64,
+	//             18,
+	//             0,
+	//             1
 	//           ],
 	//           "c": {
 	//             "ai/llm": [
 	//               4,
 	//               0,
 	//               1,
-	//               1
+	//               1,
+	//               0
 	//             ]
 	//           }
 	//         }
@@ -239,19 +315,27 @@ func ExampleParse() {
 	//         0,
 	//         2000,
 	//         0,
-	//         1
+	//         1,
+	//         2,
+	//         0,
+	//         0,
+	//         36
 	//       ],
 	//       "b": [
 	//         {
 	//           "d": [
-	//             64
+	//             64,
+	//             18,
+	//             0,
+	//             1
 	//           ],
 	//           "c": {
 	//             "ai/llm": [
 	//               8,
 	//               1,
 	//               1,
-	//               1
+	//               1,
+	//               0
 	//             ]
 	//           }
 	//         }
@@ -265,12 +349,19 @@ func ExampleParse() {
 	//         0,
 	//         2000,
 	//         0,
-	//         1
+	//         1,
+	//         2,
+	//         0,
+	//         0,
+	//         40
 	//       ],
 	//       "b": [
 	//         {
 	//           "d": [
-	//             64
+	//             64,
+	//             0,
+	//             0,
+	//             0
 	//           ],
 	//           "c": {}
 	//         }
@@ -286,12 +377,19 @@ func ExampleParse() {
 	//         0,
 	//         2000,
 	//         0,
-	//         0
+	//         0,
+	//         1,
+	//         0,
+	//         0,
+	//         40
 	//       ],
 	//       "b": [
 	//         {
 	//           "d": [
-	//             64
+	//             64,
+	//             0,
+	//             0,
+	//             0
 	//           ],
 	//           "c": {}
 	//         }
@@ -307,12 +405,19 @@ func ExampleParse() {
 	//         0,
 	//         2000,
 	//         0,
-	//         1
+	//         1,
+	//         16,
+	//         0,
+	//         0,
+	//         40
 	//       ],
 	//       "b": [
 	//         {
 	//           "d": [
-	//             64
+	//             64,
+	//             0,
+	//             0,
+	//             0
 	//           ],
 	//           "c": {}
 	//         }
@@ -328,12 +433,19 @@ func ExampleParse() {
 	//         0,
 	//         2000,
 	//         0,
-	//         1
+	//         1,
+	//         2,
+	//         0,
+	//         0,
+	//         44
 	//       ],
 	//       "b": [
 	//         {
 	//           "d": [
-	//             64
+	//             64,
+	//             0,
+	//             0,
+	//             0
 	//           ],
 	//           "c": {}
 	//         }
@@ -349,24 +461,37 @@ func ExampleParse() {
 	//         0,
 	//         10000,
 	//         8,
-	//         2
+	//         2,
+	//         1,
+	//         0,
+	//         0,
+	//         48
 	//       ],
 	//       "b": [
 	//         {
 	//           "d": [
-	//             64
+	//             64,
+	//             0,
+	//             0,
+	//             0
 	//           ],
 	//           "c": {}
 	//         },
 	//         {
 	//           "d": [
-	//             64
+	//             64,
+	//             0,
+	//             0,
+	//             0
 	//           ],
 	//           "c": {}
 	//         },
 	//         {
 	//           "d": [
-	//             64
+	//             64,
+	//             0,
+	//             0,
+	//             0
 	//           ],
 	//           "c": {}
 	//         }
@@ -381,11 +506,14 @@ func ExampleParse() {
 	//   "c": {
 	//     "ai/mcp": [
 	//       2,
-	//       3
+	//       3,
+	//       2
 	//     ],
 	//     "auth/api-keys": [],
 	//     "github.com/devopsfaith/krakend-lua/router": [
-	//       1
+	//       1,
+	//       0,
+	//       15
 	//     ],
 	//     "github_com/devopsfaith/bloomfilter": [
 	//       1,
@@ -405,13 +533,21 @@ func ExampleParse() {
 	//     "modifier/response-headers": [
 	//       15
 	//     ],
-	//     "qos/ratelimit/service": [],
+	//     "qos/ratelimit/service": [
+	//       1,
+	//       180000,
+	//       0,
+	//       0,
+	//       0
+	//     ],
 	//     "telemetry/opentelemetry": [
 	//       50,
 	//       100,
 	//       1,
 	//       2,
-	//       1
+	//       1,
+	//       3,
+	//       3
 	//     ]
 	//   }
 	// }
@@ -432,6 +568,21 @@ func ExampleParse_withRevokerServer() {
 	// output:
 	// {
 	//   "d": [
+	//     0,
+	//     0,
+	//     0,
+	//     0,
+	//     0,
+	//     0,
+	//     0,
+	//     0,
+	//     0,
+	//     0,
+	//     0,
+	//     0,
+	//     0,
+	//     0,
+	//     0,
 	//     0
 	//   ],
 	//   "a": null,
@@ -443,12 +594,18 @@ func ExampleParse_withRevokerServer() {
 	//         0,
 	//         2000,
 	//         0,
+	//         0,
+	//         1,
+	//         0,
 	//         0
 	//       ],
 	//       "b": [
 	//         {
 	//           "d": [
-	//             64
+	//             64,
+	//             65,
+	//             0,
+	//             1
 	//           ],
 	//           "c": {}
 	//         }
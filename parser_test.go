@@ -1,8 +1,13 @@
 package audit
 
 import (
+	"crypto/tls"
+	"reflect"
 	"testing"
+	"time"
 
+	ratelimitProxy "github.com/krakend/krakend-ratelimit/v3/proxy"
+	ratelimit "github.com/krakend/krakend-ratelimit/v3/router"
 	"github.com/luraproject/lura/v2/config"
 	"github.com/luraproject/lura/v2/encoding"
 	router "github.com/luraproject/lura/v2/router/gin"
@@ -17,6 +22,17 @@ func TestParse(t *testing.T) {
 	cfg.TLS.EnableMTLS = true
 	cfg.TLS.DisableSystemCaPool = true
 	cfg.TLS.CaCerts = []string{"path/to/cacert"}
+	cfg.TLS.MinVersion = "TLS11"
+	cfg.TLS.CipherSuites = []uint16{tls.TLS_RSA_WITH_RC4_128_SHA, tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA}
+	cfg.TLS.CurvePreferences = []uint16{uint16(tls.X25519)}
+	cfg.ReadTimeout = 2 * time.Second
+	cfg.WriteTimeout = 3 * time.Second
+	cfg.ReadHeaderTimeout = 500 * time.Millisecond
+	cfg.MaxHeaderBytes = 4096
+	cfg.ClientTLS = &config.ClientTLS{
+		MinVersion:   "TLS12",
+		CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+	}
 	cfg.ExtraConfig[router.Namespace] = map[string]interface{}{
 		"error_body":                        true,
 		"disable_health":                    true,
@@ -38,8 +54,12 @@ func TestParse(t *testing.T) {
 		"hide_version_header":               true,
 	}
 	cfg.Endpoints[0].OutputEncoding = encoding.SAFE_JSON
+	cfg.Endpoints[0].CacheTTL = time.Minute
+	cfg.Endpoints[0].HeadersToPass = []string{"Authorization"}
 	cfg.Endpoints[0].Backend[0].Target = "foo"
 	cfg.Endpoints[0].Backend[0].IsCollection = true
+	cfg.Endpoints[0].Backend[0].Host = []string{"http://10.0.0.1:8080", "https://api.example.com", "localhost:8000"}
+	cfg.Endpoints[0].Backend[0].SD = "dns"
 	cfg.Normalize()
 
 	result := Parse(&cfg)
@@ -52,32 +72,243 @@ func TestParse(t *testing.T) {
 		t.Errorf("unexpected number of agents. have: %d, want: %d", len(result.Agents), len(cfg.AsyncAgents))
 	}
 
-	if len(result.Details) != 1 {
-		t.Errorf("unexpected number of details. have: %d, want: 1", len(result.Details))
+	if len(result.Details) != ServiceDetailMaxHeaderBytes+1 {
+		t.Errorf("unexpected number of details. have: %d, want: %d", len(result.Details), ServiceDetailMaxHeaderBytes+1)
 		return
 	}
 
+	for i, v := range []int{
+		tls.VersionTLS11,
+		tls.VersionTLS13,
+		1<<TLSCipherSuitesCustom | 1<<TLSCipherSuitesInsecure,
+		1,
+		1,
+		tls.VersionTLS12,
+		tls.VersionTLS13,
+		1 << TLSCipherSuitesCustom,
+		0,
+		0,
+		2000,
+		3000,
+		0,
+		500,
+		4096,
+	} {
+		if result.Details[i+1] != v {
+			t.Errorf("unexpected service detail %d. have: %d, want: %d", i+1, result.Details[i+1], v)
+		}
+	}
+
 	if result.Details[0] != 8124 {
 		t.Errorf("unexpected service details. have: %d, want: 4028", result.Details[0])
 	}
 
-	if len(result.Endpoints[0].Details) != 6 {
-		t.Errorf("unexpected number of endpoint details. have: %d, want: 5", len(result.Endpoints[0].Details))
+	if len(result.Endpoints[0].Details) != EndpointDetailRoute+2 {
+		t.Errorf("unexpected number of endpoint details. have: %d, want: %d", len(result.Endpoints[0].Details), EndpointDetailRoute+2)
 		return
 	}
 
-	for i, v := range []int{4, 0, 0, 140000} {
+	for i, v := range []int{4, 0, 1, 140000} {
 		if result.Endpoints[0].Details[i] != v {
 			t.Errorf("unexpected endpoint details. have: %d, want: %d", result.Endpoints[0].Details[i], v)
 		}
 	}
 
-	if len(result.Endpoints[0].Backends[0].Details) != 1 {
-		t.Errorf("unexpected number of backend details. have: %d, want: 1", len(result.Endpoints[0].Backends[0].Details))
+	if v := result.Endpoints[0].Details[EndpointDetailMethod]; v != 1<<MethodGET {
+		t.Errorf("unexpected endpoint method. have: %d, want: %d", v, 1<<MethodGET)
+	}
+	if v := result.Endpoints[0].Details[EndpointDetailCacheTTL]; v != 60000 {
+		t.Errorf("unexpected endpoint cache_ttl. have: %d, want: 60000", v)
+	}
+	if v := result.Endpoints[0].Details[EndpointDetailCredentialHeaders]; v != 1<<CredentialHeaderAuthorization {
+		t.Errorf("unexpected endpoint credential headers. have: %d, want: %d", v, 1<<CredentialHeaderAuthorization)
+	}
+
+	if len(result.Endpoints[0].Backends[0].Details) != BackendDetailHostCount+1 {
+		t.Errorf("unexpected number of backend details. have: %d, want: %d", len(result.Endpoints[0].Backends[0].Details), BackendDetailHostCount+1)
 		return
 	}
 
 	if result.Endpoints[0].Backends[0].Details[0] != 6208 {
 		t.Errorf("unexpected backend details. have: %d, want: 6208", result.Endpoints[0].Backends[0].Details[0])
 	}
+
+	hosts := 1<<BackendHostHTTP | 1<<BackendHostHTTPS | 1<<BackendHostName |
+		1<<BackendHostIP | 1<<BackendHostLoopback | 1<<BackendHostPrivate
+	for i, v := range []int{hosts, BackendSDDNS, 3} {
+		if result.Endpoints[0].Backends[0].Details[i+1] != v {
+			t.Errorf("unexpected backend detail %d. have: %d, want: %d", i+1, result.Endpoints[0].Backends[0].Details[i+1], v)
+		}
+	}
+}
+
+func Test_parseRoute(t *testing.T) {
+	for path, kinds := range map[string][]int{
+		"/":                      nil,
+		"/users":                 {RouteSegmentLiteral},
+		"/users/{id}/":           {RouteSegmentLiteral, RouteSegmentParam},
+		"/users/:id/posts":       {RouteSegmentLiteral, RouteSegmentParam, RouteSegmentLiteral},
+		"/wildcarded/resource/*": {RouteSegmentLiteral, RouteSegmentLiteral, RouteSegmentWildcard},
+	} {
+		route := parseRoute(path, routeSegmentNames{})
+		if len(route) != len(kinds) {
+			t.Errorf("%s: unexpected number of segments. have: %d, want: %d", path, len(route), len(kinds))
+			continue
+		}
+		for i, kind := range kinds {
+			if routeSegmentKind(route[i]) != kind {
+				t.Errorf("%s: unexpected kind of segment %d. have: %d, want: %d", path, i, routeSegmentKind(route[i]), kind)
+			}
+		}
+	}
+
+	names := routeSegmentNames{}
+	if parseRoute("/users/{id}", names)[1] != parseRoute("/users/:id", names)[1] {
+		t.Error("the parameter notations are not equivalent")
+	}
+	if parseRoute("/users/{id}", names)[1] == parseRoute("/users/{name}", names)[1] {
+		t.Error("the parameter names are not kept")
+	}
+	if parseRoute("/users", names)[0] == parseRoute("/posts", names)[0] {
+		t.Error("different literals share the same segment")
+	}
+	if parseRoute("/users", routeSegmentNames{})[0] != parseRoute("/posts", routeSegmentNames{})[0] {
+		t.Error("the segment identifiers do not depend on the order of appearance")
+	}
+}
+
+func Test_parseComponents_ratelimit(t *testing.T) {
+	components := parseComponents(config.ExtraConfig{
+		ratelimit.Namespace: map[string]interface{}{
+			"max_rate":        100.0,
+			"capacity":        100.0,
+			"client_max_rate": 10.0,
+			"strategy":        "header",
+			"key":             "X-Api-Key",
+			"every":           "1m",
+		},
+		ratelimitProxy.Namespace: map[string]interface{}{
+			"max_rate": 0.5,
+			"capacity": 1.0,
+		},
+	})
+
+	flags := 1<<RatelimitMaxRate | 1<<RatelimitClientMaxRate | 1<<RatelimitStrategyHeader | 1<<RatelimitKey
+	for i, v := range []int{flags, 6000, 100, 600, 0} {
+		if components[ratelimit.Namespace][i] != v {
+			t.Errorf("unexpected router rate limit detail %d. have: %d, want: %d", i, components[ratelimit.Namespace][i], v)
+		}
+	}
+	for i, v := range []int{1800, 1} {
+		if components[ratelimitProxy.Namespace][i] != v {
+			t.Errorf("unexpected proxy rate limit detail %d. have: %d, want: %d", i, components[ratelimitProxy.Namespace][i], v)
+		}
+	}
+}
+
+func Test_parseComponents_websocketMaxMessageSize(t *testing.T) {
+	for size, expected := range map[float64]int{4096: 4096, 0: 0, -1: 0} {
+		components := parseComponents(config.ExtraConfig{
+			"websocket": map[string]interface{}{"max_message_size": size},
+		})
+		if v := components["websocket"]; len(v) < 5 || v[4] != expected {
+			t.Errorf("%v: unexpected max message size %v", size, v)
+		}
+	}
+}
+
+func Test_parseComponents_llm(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg      map[string]interface{}
+		expected []int
+	}{
+		"known version": {
+			cfg:      map[string]interface{}{"openai": map[string]interface{}{"v1": map[string]interface{}{"input_template": "x"}}},
+			expected: []int{1 << 1, 1, 0, 1, 0},
+		},
+		"outdated version": {
+			cfg:      map[string]interface{}{"openai": map[string]interface{}{"v0": map[string]interface{}{}}},
+			expected: []int{0, 0, 0, 0, 1 << 1},
+		},
+		"newer version": {
+			cfg:      map[string]interface{}{"openai": map[string]interface{}{"v2": map[string]interface{}{}}},
+			expected: []int{0, 0, 0, 0, 0},
+		},
+		"both versions": {
+			cfg:      map[string]interface{}{"gemini": map[string]interface{}{"v1beta": map[string]interface{}{}, "v1alpha": map[string]interface{}{}}},
+			expected: []int{1, 0, 0, 1, 1},
+		},
+		"unknown provider": {
+			cfg:      map[string]interface{}{"other": map[string]interface{}{"v1": map[string]interface{}{}}},
+			expected: []int{0, 0, 0, 0, 0},
+		},
+	} {
+		components := parseComponents(config.ExtraConfig{"ai/llm": tc.cfg})
+		if !reflect.DeepEqual(components["ai/llm"], tc.expected) {
+			t.Errorf("%s: unexpected details. have: %v, want: %v", name, components["ai/llm"], tc.expected)
+		}
+	}
+}
+
+func Test_parseComponents_mcp(t *testing.T) {
+	components := parseComponents(config.ExtraConfig{"ai/mcp": map[string]interface{}{}})
+	if v, ok := components["ai/mcp"]; !ok || !reflect.DeepEqual(v, []int{0, 0, 0}) {
+		t.Errorf("unexpected mcp details: %v", v)
+	}
+}
+
+func Test_isOutdatedAPIVersion(t *testing.T) {
+	for k, expected := range map[string]bool{
+		"v0":       true,
+		"v1beta":   true,
+		"v1alpha2": true,
+		"v1":       false,
+		"v2":       false,
+		"v2beta":   false,
+		"other":    false,
+	} {
+		if isOutdatedAPIVersion(k, []string{"v1"}) != expected {
+			t.Errorf("unexpected result for %s", k)
+		}
+	}
+	if isOutdatedAPIVersion("v1beta2", []string{"v1beta"}) {
+		t.Error("a later revision is not outdated")
+	}
+}
+
+func Test_parseComponents_modifiers(t *testing.T) {
+	components := parseComponents(config.ExtraConfig{
+		responseBodyModifierNamespace: map[string]interface{}{
+			"modifiers": []interface{}{
+				map[string]interface{}{"regexp": map[string]interface{}{}},
+				map[string]interface{}{"upper": map[string]interface{}{}},
+				"regexp",
+				map[string]interface{}{"unknown": map[string]interface{}{}},
+			},
+		},
+	})
+	if v := components[responseBodyModifierNamespace]; !reflect.DeepEqual(v, []int{4, 1, 0, 1, 0, 0}) {
+		t.Errorf("unexpected modifier details: %v", v)
+	}
+}
+
+func Test_parseHosts(t *testing.T) {
+	for host, plainRemote := range map[string]bool{
+		"http://localhost:8080":   false,
+		"http://127.0.0.1":        false,
+		"http://10.0.0.1:8080":    false,
+		"http://192.168.1.10":     false,
+		"http://203.0.113.10":     true,
+		"http://api.example.com":  true,
+		"api.example.com:8080":    true,
+		"https://api.example.com": false,
+		"HTTP://203.0.113.10":     true,
+		"localhost:4317":          false,
+		"http://[::1]:4317":       false,
+		"grpc://api.example.com":  false,
+	} {
+		if hasBit(parseHosts([]string{host}), BackendHostPlainRemote) != plainRemote {
+			t.Errorf("%s: unexpected plain remote bit. want: %v", host, plainRemote)
+		}
+	}
 }
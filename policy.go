@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"fmt"
+	"path"
+	"sort"
+)
+
+// Policy describes the conditions an AuditResult must meet to pass a quality gate, such as
+// a CI pipeline. An empty policy accepts any result
+type Policy struct {
+	// MaxFindings limits the number of findings of each severity, counting every location
+	// of the recommendations. A limit of 0 forbids the severity, and severities not present
+	// in the map are not limited
+	MaxFindings map[Severity]int `json:"max_findings,omitempty"`
+	// BlockingRules lists the ids, or glob patterns of ids (e.g. "2.1.*"), of the rules
+	// that fail the policy whenever they have a recommendation
+	BlockingRules []string `json:"blocking_rules,omitempty"`
+	// OnlyNew restricts the evaluation to the new recommendations when the result has
+	// been compared with a baseline
+	OnlyNew bool `json:"only_new,omitempty"`
+	// MinScore is the lowest overall score accepted. A value of 0 disables the condition
+	MinScore float64 `json:"min_score,omitempty"`
+}
+
+// Verdict is the outcome of the evaluation of a Policy
+type Verdict struct {
+	Pass       bool        `json:"pass"`
+	Violations []Violation `json:"violations"`
+}
+
+// Violation describes a condition of the policy that the result does not meet
+type Violation struct {
+	Condition string   `json:"condition"`
+	Rule      string   `json:"rule,omitempty"`
+	Severity  Severity `json:"severity,omitempty"`
+	Limit     float64  `json:"limit"`
+	Actual    float64  `json:"actual"`
+	Message   string   `json:"message"`
+}
+
+const (
+	ConditionMaxFindings  = "max_findings"
+	ConditionBlockingRule = "blocking_rule"
+	ConditionMinScore     = "min_score"
+)
+
+// Validate checks the severities and the patterns of the policy
+func (p Policy) Validate() error {
+	for s, limit := range p.MaxFindings {
+		if !s.IsValid() {
+			return fmt.Errorf("%w: %q", ErrInvalidSeverity, string(s))
+		}
+		if limit < 0 {
+			return fmt.Errorf("invalid limit for %s findings: %d", s, limit)
+		}
+	}
+	if p.MinScore < 0 || p.MinScore > 100 {
+		return fmt.Errorf("invalid min score: %f", p.MinScore)
+	}
+	for _, pattern := range p.BlockingRules {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid rule pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Check evaluates the policy against the result
+func (r AuditResult) Check(p Policy) (Verdict, error) {
+	if err := p.Validate(); err != nil {
+		return Verdict{}, err
+	}
+
+	recommendations := r.Recommendations
+	if p.OnlyNew && r.Baseline != nil {
+		recommendations = r.Baseline.New
+	}
+
+	v := Verdict{Violations: []Violation{}}
+
+	bySeverity := map[Severity]int{}
+	for _, rec := range recommendations {
+		bySeverity[rec.Severity] += findings(rec)
+	}
+	severities := make([]Severity, 0, len(p.MaxFindings))
+	for s := range p.MaxFindings {
+		severities = append(severities, s)
+	}
+	sort.Slice(severities, func(i, j int) bool { return severities[i].Compare(severities[j]) > 0 })
+	for _, s := range severities {
+		limit := p.MaxFindings[s]
+		if bySeverity[s] <= limit {
+			continue
+		}
+		v.Violations = append(v.Violations, Violation{
+			Condition: ConditionMaxFindings,
+			Severity:  s,
+			Limit:     float64(limit),
+			Actual:    float64(bySeverity[s]),
+			Message:   fmt.Sprintf("%d %s findings, at most %d allowed", bySeverity[s], s, limit),
+		})
+	}
+
+	for _, rec := range recommendations {
+		if !matchesAny(p.BlockingRules, rec.Rule) {
+			continue
+		}
+		v.Violations = append(v.Violations, Violation{
+			Condition: ConditionBlockingRule,
+			Rule:      rec.Rule,
+			Severity:  rec.Severity,
+			Actual:    float64(len(rec.Locations)),
+			Message:   fmt.Sprintf("rule %s is blocking: %s", rec.Rule, rec.Message),
+		})
+	}
+
+	if p.MinScore > 0 && r.Score.Overall < p.MinScore {
+		v.Violations = append(v.Violations, Violation{
+			Condition: ConditionMinScore,
+			Limit:     p.MinScore,
+			Actual:    r.Score.Overall,
+			Message:   fmt.Sprintf("score %.2f is below %.2f", r.Score.Overall, p.MinScore),
+		})
+	}
+
+	v.Pass = len(v.Violations) == 0
+	return v, nil
+}
+
+// findings returns the number of locations of the recommendation, counting as a single
+// finding the recommendations without locations
+func findings(r Recommendation) int {
+	return max(len(r.Locations), 1)
+}
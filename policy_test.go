@@ -0,0 +1,123 @@
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAuditResult_Check(t *testing.T) {
+	result, err := Run(loadExample1(t), WithSections("2", "3"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, tc := range []struct {
+		name       string
+		policy     string
+		violations []Violation
+	}{
+		{
+			name:   "empty",
+			policy: `{}`,
+		},
+		{
+			name:   "loose",
+			policy: `{"max_findings":{"CRITICAL":2,"high":10},"blocking_rules":["1.*","2.1.1"]}`,
+		},
+		{
+			name:   "strict",
+			policy: `{"max_findings":{"HIGH":3,"critical":0,"LOW":5},"blocking_rules":["2.1.3","2.2.*"]}`,
+			violations: []Violation{
+				{Condition: ConditionMaxFindings, Severity: SeverityCritical, Limit: 0, Actual: 2},
+				{Condition: ConditionMaxFindings, Severity: SeverityHigh, Limit: 3, Actual: 8},
+				{Condition: ConditionMaxFindings, Severity: SeverityLow, Limit: 5, Actual: 9},
+				{Condition: ConditionBlockingRule, Rule: "2.1.3", Severity: SeverityCritical, Actual: 1},
+				{Condition: ConditionBlockingRule, Rule: "2.2.1", Severity: SeverityMedium, Actual: 1},
+				{Condition: ConditionBlockingRule, Rule: "2.2.2", Severity: SeverityHigh, Actual: 1},
+				{Condition: ConditionBlockingRule, Rule: "2.2.3", Severity: SeverityHigh, Actual: 1},
+				{Condition: ConditionBlockingRule, Rule: "2.2.4", Severity: SeverityHigh, Actual: 1},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var p Policy
+			if err := json.Unmarshal([]byte(tc.policy), &p); err != nil {
+				t.Error(err)
+				return
+			}
+			v, err := result.Check(p)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if v.Pass != (len(tc.violations) == 0) {
+				t.Errorf("unexpected verdict: %+v", v)
+			}
+			if len(v.Violations) != len(tc.violations) {
+				t.Errorf("unexpected number of violations. have: %d, want: %d: %+v", len(v.Violations), len(tc.violations), v.Violations)
+				return
+			}
+			for i, expected := range tc.violations {
+				have := v.Violations[i]
+				have.Message = ""
+				if have != expected {
+					t.Errorf("unexpected violation %d. have: %+v, want: %+v", i, have, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestAuditResult_Check_locations(t *testing.T) {
+	result := AuditResult{
+		Recommendations: []Recommendation{
+			{Rule: "2.2.3", Severity: SeverityHigh, Locations: []Location{{Pointer: "/endpoints/0"}, {Pointer: "/endpoints/1"}, {Pointer: "/endpoints/2"}}},
+			{Rule: "2.2.4", Severity: SeverityHigh},
+		},
+	}
+	v, err := result.Check(Policy{MaxFindings: map[Severity]int{SeverityHigh: 3}})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if v.Pass || len(v.Violations) != 1 || v.Violations[0].Actual != 4 {
+		t.Errorf("unexpected verdict: %+v", v)
+	}
+}
+
+func TestAuditResult_Check_onlyNew(t *testing.T) {
+	result := AuditResult{
+		Recommendations: []Recommendation{{Rule: "2.1.3", Severity: SeverityCritical}, {Rule: "2.2.3", Severity: SeverityHigh}},
+		Baseline: &BaselineDiff{
+			New: []Recommendation{{Rule: "2.2.3", Severity: SeverityHigh}},
+		},
+	}
+	p := Policy{MaxFindings: map[Severity]int{SeverityCritical: 0}, OnlyNew: true}
+
+	v, err := result.Check(p)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !v.Pass {
+		t.Errorf("unexpected violations: %+v", v.Violations)
+	}
+
+	p.BlockingRules = []string{"2.2.3"}
+	if v, _ := result.Check(p); v.Pass {
+		t.Error("the new finding should fail the policy")
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	for _, p := range []Policy{
+		{MaxFindings: map[Severity]int{"SEVERE": 1}},
+		{MaxFindings: map[Severity]int{SeverityHigh: -1}},
+		{BlockingRules: []string{"["}},
+	} {
+		if err := p.Validate(); err == nil {
+			t.Errorf("expecting an error validating %+v", p)
+		}
+	}
+}
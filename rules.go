@@ -1,6 +1,11 @@
 package audit
 
 import (
+	"crypto/tls"
+	"slices"
+	"strconv"
+
+	bf "github.com/krakend/bloomfilter/v2/krakend"
 	botdetector "github.com/krakend/krakend-botdetector/v2/krakend"
 	cb "github.com/krakend/krakend-circuitbreaker/v3/gobreaker"
 	cors "github.com/krakend/krakend-cors/v2"
@@ -11,6 +16,8 @@ import (
 	influx "github.com/krakend/krakend-influx/v2"
 	jose "github.com/krakend/krakend-jose/v2"
 	logstash "github.com/krakend/krakend-logstash/v2"
+	luaproxy "github.com/krakend/krakend-lua/v2/proxy"
+	luarouter "github.com/krakend/krakend-lua/v2/router"
 	metrics "github.com/krakend/krakend-metrics/v2"
 	opencensus "github.com/krakend/krakend-opencensus/v2"
 	ratelimitProxy "github.com/krakend/krakend-ratelimit/v3/proxy"
@@ -26,235 +33,508 @@ func hasBit(x, y int) bool {
 	return (x>>y)&1 == 1
 }
 
-func hasBasicAuth(s *Service) bool {
+func hasBasicAuth(s *Service) []Scope {
+	var res []Scope
 	// check basic auth in plugin
 	if len(s.Components[server.Namespace]) > 0 && hasBit(s.Components[server.Namespace][0], parseServerPlugin("basic-auth")) {
 		// old plugin basic auth
-		return true
+		res = append(res, serviceScope(extraConfig(server.Namespace)...))
 	}
 	if len(s.Components["auth/basic"]) > 0 && hasBit(s.Components["auth/basic"][0], 0) {
 		// main server config has auth/basic enabled
-		return true
+		res = append(res, serviceScope(extraConfig("auth/basic")...))
 	}
 
-	for _, e := range s.Endpoints {
+	for i, e := range s.Endpoints {
 		if len(e.Components["auth/basic"]) > 0 && hasBit(e.Components["auth/basic"][0], 0) {
-			return true
+			res = append(res, endpointScope(i, extraConfig("auth/basic")...))
 		}
 	}
 
-	return false
+	return res
 }
 
-func hasTelemetryMissingName(_ *Service) bool {
+func hasTelemetryMissingName(_ *Service) []Scope {
 	// TODO: implement this check
-	return false
+	return nil
 }
 
-func hasDeprecatedServerPlugin(pluginName string) func(s *Service) bool {
-	return func(s *Service) bool {
+func hasDeprecatedServerPlugin(pluginName string) func(s *Service) []Scope {
+	return func(s *Service) []Scope {
 		serverPlugins, ok := s.Components[server.Namespace]
 		if !ok {
-			return false
+			return nil
 		}
 		if len(serverPlugins) < 1 {
-			return false
+			return nil
 		}
 		if hasBit(serverPlugins[0], parseServerPlugin(pluginName)) {
-			return true
+			return []Scope{serviceScope(extraConfig(server.Namespace)...)}
 		}
-		return false
+		return nil
 	}
 }
 
-func hasDeprecatedClientPlugin(pluginName string) func(s *Service) bool {
-	return func(s *Service) bool {
+func hasDeprecatedClientPlugin(pluginName string) func(s *Service) []Scope {
+	return func(s *Service) []Scope {
+		var res []Scope
 		compID := parseClientPlugin(pluginName)
-		for _, ep := range s.Endpoints {
+		for i, ep := range s.Endpoints {
 			comp, ok := ep.Components[client.Namespace]
 			if ok && len(comp) > 0 && comp[0] == compID {
-				return true
+				res = append(res, endpointScope(i, extraConfig(client.Namespace)...))
 			}
 		}
-		return false
+		return res
 	}
 }
 
-func hasDeprecatedReqRespPlugin(pluginName string) func(s *Service) bool {
-	return func(s *Service) bool {
+func hasDeprecatedReqRespPlugin(pluginName string) func(s *Service) []Scope {
+	return func(s *Service) []Scope {
+		var res []Scope
 		id := parseRespReqPlugin(pluginName)
-		for _, ep := range s.Endpoints {
+		for i, ep := range s.Endpoints {
 			comp, ok := ep.Components[plugin.Namespace]
-			if ok && hasBit(comp[0], id) {
-				return true
+			if ok && len(comp) > 0 && hasBit(comp[0], id) {
+				res = append(res, endpointScope(i, extraConfig(plugin.Namespace)...))
 			}
-			for _, b := range ep.Backends {
+			for j, b := range ep.Backends {
 				comp, ok := b.Components[plugin.Namespace]
-				if ok && hasBit(comp[0], id) {
-					return true
+				if ok && len(comp) > 0 && hasBit(comp[0], id) {
+					res = append(res, endpointBackendScope(i, j, extraConfig(plugin.Namespace)...))
 				}
 			}
 		}
-		return false
+		return res
 	}
 }
 
-func hasApiKeys(s *Service) bool {
-	_, ok := s.Components["auth/api-keys"]
-	return ok
+func hasApiKeys(s *Service) []Scope {
+	if _, ok := s.Components["auth/api-keys"]; ok {
+		return []Scope{serviceScope(extraConfig("auth/api-keys")...)}
+	}
+	return nil
 }
 
-func hasNoJWT(s *Service) bool {
+func hasNoJWT(s *Service) []Scope {
+	if hasJWTValidation(s) {
+		return nil
+	}
+	return []Scope{serviceScope("endpoints")}
+}
+
+func hasJWTValidation(s *Service) bool {
 	for _, e := range s.Endpoints {
 		if _, ok := e.Components[jose.ValidatorNamespace]; ok {
-			return false
+			return true
 		}
 	}
-	return true
+	return false
+}
+
+func hasNoRevocation(s *Service) []Scope {
+	if _, ok := s.Components[bf.Namespace]; ok || !hasJWTValidation(s) {
+		return nil
+	}
+	return []Scope{serviceScope(extraConfig(bf.Namespace)...)}
+}
+
+func hasRevocationWithoutTokenKeys(s *Service) []Scope {
+	v, ok := s.Components[bf.Namespace]
+	if ok && len(v) > 1 && v[1] == 0 {
+		return []Scope{serviceScope(extraConfig(bf.Namespace, "token_keys")...)}
+	}
+	return nil
+}
+
+func hasRevocationWithoutJWT(s *Service) []Scope {
+	if _, ok := s.Components[bf.Namespace]; ok && !hasJWTValidation(s) {
+		return []Scope{serviceScope(extraConfig(bf.Namespace)...)}
+	}
+	return nil
+}
+
+func hasRevocationNonOptimalHash(s *Service) []Scope {
+	v, ok := s.Components[bf.Namespace]
+	if ok && len(v) > 0 && v[0] == 0 {
+		return []Scope{serviceScope(extraConfig(bf.Namespace, "hash_name")...)}
+	}
+	return nil
 }
 
-func hasInsecureConnections(s *Service) bool {
-	return hasBit(s.Details[0], ServiceAllowInsecureConnections)
+func hasInsecureConnections(s *Service) []Scope {
+	if !hasBit(s.Details[0], ServiceAllowInsecureConnections) {
+		return nil
+	}
+	if hasBit(s.Details[0], ServiceClientTLSAllowInsecureConnections) {
+		return []Scope{serviceScope("client_tls", "allow_insecure_connections")}
+	}
+	return []Scope{serviceScope("allow_insecure_connections")}
 }
 
-func hasNoTLS(s *Service) bool {
-	return !hasBit(s.Details[0], ServiceHasTLS)
+func hasNoTLS(s *Service) []Scope {
+	if !hasBit(s.Details[0], ServiceHasTLS) {
+		return []Scope{serviceScope("tls")}
+	}
+	return nil
 }
 
-func hasTLSDisabled(s *Service) bool {
-	return hasBit(s.Details[0], ServiceHasTLS) && !hasBit(s.Details[0], ServiceTLSEnabled)
+func hasTLSDisabled(s *Service) []Scope {
+	if hasBit(s.Details[0], ServiceHasTLS) && !hasBit(s.Details[0], ServiceTLSEnabled) {
+		return []Scope{serviceScope("tls", "disabled")}
+	}
+	return nil
 }
 
-func hasNoHTTPSecure(s *Service) bool {
-	_, ok := s.Components[httpsecure.Namespace]
-	return !ok
+func hasNoHTTPSecure(s *Service) []Scope {
+	if _, ok := s.Components[httpsecure.Namespace]; !ok {
+		return []Scope{serviceScope(extraConfig(httpsecure.Namespace)...)}
+	}
+	return nil
 }
 
-func hasH2C(s *Service) bool {
+func hasH2C(s *Service) []Scope {
+	var res []Scope
 	if hasBit(s.Details[0], ServiceUseH2C) {
-		return true
+		res = append(res, serviceScope("use_h2c"))
 	}
 	// this is the deprecated way of assing h2c
 	v, ok := s.Components[router.Namespace]
-	if !ok || len(v) == 0 {
-		return false
+	if ok && len(v) > 0 && hasBit(v[0], RouterUseH2C) {
+		res = append(res, serviceScope(extraConfig(router.Namespace, "use_h2c")...))
 	}
-	return hasBit(v[0], RouterUseH2C)
+	return res
 }
 
-func hasBackendInsecureConnections(s *Service) bool {
-	for _, e := range s.Endpoints {
-		for _, b := range e.Backends {
-			v, ok := b.Components["backend/http/client"]
-			if !ok || len(v) == 0 {
-				continue
-			}
-			if hasBit(v[0], BackendComponentHTTPClientAllowInsecureConnections) {
-				return true
+func hasBackendInsecureConnections(s *Service) []Scope {
+	var res []Scope
+	scopes, backends := allBackends(s)
+	for i, b := range backends {
+		v, ok := b.Components["backend/http/client"]
+		if !ok || len(v) == 0 {
+			continue
+		}
+		if hasBit(v[0], BackendComponentHTTPClientAllowInsecureConnections) {
+			res = append(res, append(scopes[i], extraConfig("backend/http/client")...))
+		}
+	}
+	return res
+}
+
+func serviceDetail(s *Service, i int) int {
+	if i < len(s.Details) {
+		return s.Details[i]
+	}
+	return 0
+}
+
+func isServerTLSEnabled(s *Service) bool {
+	return len(s.Details) > 0 && hasBit(s.Details[0], ServiceHasTLS) && hasBit(s.Details[0], ServiceTLSEnabled)
+}
+
+// backendTLS returns the scopes of the backends declaring a client_tls section in their
+// http client options, along with the parsed minimum version and cipher suites
+func backendTLS(s *Service) (scopes []Scope, versions, ciphers []int) {
+	add := func(b Backend, scope Scope) {
+		v, ok := b.Components["backend/http/client"]
+		if !ok || len(v) < 3 || v[1] == 0 {
+			return
+		}
+		scopes = append(scopes, scope)
+		versions = append(versions, v[1])
+		ciphers = append(ciphers, v[2])
+	}
+	for i, e := range s.Endpoints {
+		for j, b := range e.Backends {
+			add(b, endpointBackendScope(i, j, extraConfig("backend/http/client", "client_tls")...))
+		}
+	}
+	for i, a := range s.Agents {
+		for j, b := range a.Backends {
+			add(b, agentBackendScope(i, j, extraConfig("backend/http/client", "client_tls")...))
+		}
+	}
+	return
+}
+
+func hasOutdatedTLSVersion(s *Service) []Scope {
+	var res []Scope
+	if v := serviceDetail(s, ServiceDetailTLSMinVersion); isServerTLSEnabled(s) && v != 0 && v < tls.VersionTLS12 {
+		res = append(res, serviceScope("tls", "min_version"))
+	}
+	if v := serviceDetail(s, ServiceDetailClientTLSMinVersion); v != 0 && v < tls.VersionTLS12 {
+		res = append(res, serviceScope("client_tls", "min_version"))
+	}
+	scopes, versions, _ := backendTLS(s)
+	for i, scope := range scopes {
+		if versions[i] < tls.VersionTLS12 {
+			res = append(res, append(scope, "min_version"))
+		}
+	}
+	return res
+}
+
+// hasTLSCipherSuites checks the declared cipher suites for the given flag. The suites
+// are ignored when the minimum version is TLS 1.3, as they are not configurable there
+func hasTLSCipherSuites(flag int) func(*Service) []Scope {
+	return func(s *Service) []Scope {
+		var res []Scope
+		if isServerTLSEnabled(s) && serviceDetail(s, ServiceDetailTLSMinVersion) < tls.VersionTLS13 &&
+			hasBit(serviceDetail(s, ServiceDetailTLSCipherSuites), flag) {
+			res = append(res, serviceScope("tls", "cipher_suites"))
+		}
+		if v := serviceDetail(s, ServiceDetailClientTLSMinVersion); v != 0 && v < tls.VersionTLS13 &&
+			hasBit(serviceDetail(s, ServiceDetailClientTLSCipherSuites), flag) {
+			res = append(res, serviceScope("client_tls", "cipher_suites"))
+		}
+		scopes, versions, ciphers := backendTLS(s)
+		for i, scope := range scopes {
+			if versions[i] < tls.VersionTLS13 && hasBit(ciphers[i], flag) {
+				res = append(res, append(scope, "cipher_suites"))
 			}
 		}
+		return res
 	}
-	return false
 }
 
-func hasEndpointWildcard(s *Service) bool {
-	for _, e := range s.Endpoints {
+var (
+	hasInsecureCipherSuites = hasTLSCipherSuites(TLSCipherSuitesInsecure)
+	hasCBCOnlyCipherSuites  = hasTLSCipherSuites(TLSCipherSuitesCBCOnly)
+)
+
+func hasMTLSWithSystemCaPool(s *Service) []Scope {
+	if isServerTLSEnabled(s) && hasBit(s.Details[0], ServiceTLSEnableMTLS) && !hasBit(s.Details[0], ServiceTLSDisableSystemCaPool) {
+		return []Scope{serviceScope("tls", "disable_system_ca_pool")}
+	}
+	return nil
+}
+
+func hasMTLSWithoutCaCerts(s *Service) []Scope {
+	if isServerTLSEnabled(s) && hasBit(s.Details[0], ServiceTLSEnableMTLS) && !hasBit(s.Details[0], ServiceTLSCaCerts) {
+		return []Scope{serviceScope("tls", "ca_certs")}
+	}
+	return nil
+}
+
+func hasEndpointWildcard(s *Service) []Scope {
+	var res []Scope
+	for i, e := range s.Endpoints {
 		if hasBit(e.Details[4], BitEndpointWildcard) {
-			return true
+			res = append(res, endpointScope(i, "endpoint"))
 		}
 	}
-	return false
+	return res
 }
 
-func hasEndpointCatchAll(s *Service) bool {
-	for _, e := range s.Endpoints {
+func hasEndpointCatchAll(s *Service) []Scope {
+	var res []Scope
+	for i, e := range s.Endpoints {
 		if hasBit(e.Details[4], BitEndpointCatchAll) {
-			return true
+			res = append(res, endpointScope(i, "endpoint"))
 		}
 	}
-	return false
+	return res
 }
 
-func hasMultipleUnsafeMethods(s *Service) bool {
-	for _, e := range s.Endpoints {
+func hasMultipleUnsafeMethods(s *Service) []Scope {
+	var res []Scope
+	for i, e := range s.Endpoints {
 		if e.Details[5] > 1 {
-			return true
+			res = append(res, endpointScope(i, "backend"))
 		}
 	}
-	return false
+	return res
 }
 
-func hasSequentialProxy(s *Service) bool {
-	for _, e := range s.Endpoints {
+func hasSequentialProxy(s *Service) []Scope {
+	var res []Scope
+	for i, e := range s.Endpoints {
 		p, ok := e.Components[proxy.Namespace]
 		if ok && len(p) > 0 && hasBit(p[0], 0) {
-			return true
+			res = append(res, endpointScope(i, extraConfig(proxy.Namespace, "sequential")...))
 		}
 	}
-	return false
+	return res
 }
 
-func hasQueryStringWildcard(s *Service) bool {
-	for _, e := range s.Endpoints {
+func hasQueryStringWildcard(s *Service) []Scope {
+	var res []Scope
+	for i, e := range s.Endpoints {
 		if hasBit(e.Details[4], 1) {
-			return true
+			res = append(res, endpointScope(i, "input_query_strings"))
 		}
 	}
-	return false
+	return res
 }
 
-func hasHeadersWildcard(s *Service) bool {
-	for _, e := range s.Endpoints {
+func hasHeadersWildcard(s *Service) []Scope {
+	var res []Scope
+	for i, e := range s.Endpoints {
 		if hasBit(e.Details[4], 2) {
-			return true
+			res = append(res, endpointScope(i, "input_headers"))
 		}
 	}
-	return false
+	return res
+}
+
+func hasNoObfuscatedVersionHeader(s *Service) []Scope {
+	v, ok := s.Components[router.Namespace]
+	if !ok || len(v) == 0 || !hasBit(v[0], RouterHideVersionHeader) {
+		return []Scope{serviceScope(extraConfig(router.Namespace, "hide_version_header")...)}
+	}
+	return nil
+}
+
+func hasRouterFlag(flag int, field string) func(*Service) []Scope {
+	return func(s *Service) []Scope {
+		v, ok := s.Components[router.Namespace]
+		if ok && len(v) > 0 && hasBit(v[0], flag) {
+			return []Scope{serviceScope(extraConfig(router.Namespace, field)...)}
+		}
+		return nil
+	}
 }
 
-func hasNoObfuscatedVersionHeader(s *Service) bool {
+var (
+	hasRouterErrorMsg      = hasRouterFlag(RouterErrorMsg, "return_error_msg")
+	hasRouterHealthOff     = hasRouterFlag(RouterDisableHealth, "disable_health")
+	hasRouterPathDecodeOff = hasRouterFlag(RouterPathDecoding, "disable_path_decoding")
+)
+
+func hasUntrustedClientIP(s *Service) []Scope {
 	v, ok := s.Components[router.Namespace]
 	if !ok || len(v) == 0 {
+		return nil
+	}
+	if hasBit(v[0], RouterForwardedByClientIp) && !hasBit(v[0], RouterTrustedProxies) {
+		return []Scope{serviceScope(extraConfig(router.Namespace, "forwarded_by_client_ip")...)}
+	}
+	return nil
+}
+
+func hasNoAccessLog(s *Service) []Scope {
+	v, ok := s.Components[router.Namespace]
+	if !ok || len(v) == 0 || !hasBit(v[0], RouterDisableAccessLog) {
+		return nil
+	}
+	// the access log can be safely disabled when another logger is in place
+	if len(hasNoLogging(s)) == 0 {
+		return nil
+	}
+	return []Scope{serviceScope(extraConfig(router.Namespace, "disable_access_log")...)}
+}
+
+func hasNoCORS(s *Service) []Scope {
+	if _, ok := s.Components[cors.Namespace]; !ok {
+		return []Scope{serviceScope(extraConfig(cors.Namespace)...)}
+	}
+	return nil
+}
+
+func hasBotdetectorDisabled(s *Service) []Scope {
+	if _, ok := s.Components[botdetector.Namespace]; !ok {
+		return []Scope{serviceScope(extraConfig(botdetector.Namespace)...)}
+	}
+	return nil
+}
+
+// botDetectorHeavyPatterns is the number of patterns from which the bot detector
+// should cache its results, as every pattern is a regular expression evaluated per request
+const botDetectorHeavyPatterns = 10
+
+// isInertBotDetector checks if the bot detector neither lists any user agent nor
+// rejects the empty ones, so it lets every request in
+func isInertBotDetector(v []int) bool {
+	if len(v) < 3 {
 		return true
 	}
-	return !hasBit(v[0], RouterHideVersionHeader)
+	return v[0] == 0 && v[1] == 0 && v[2] == 0 && (len(v) < 5 || v[4] == 0)
 }
 
-func hasNoCORS(s *Service) bool {
-	_, ok := s.Components[cors.Namespace]
-	return !ok
+// botDetectors returns the scopes of the bot detectors declared at service and
+// endpoint level along with their parsed details
+func botDetectors(s *Service) ([]Scope, [][]int) {
+	var scopes []Scope
+	var details [][]int
+	if v, ok := s.Components[botdetector.Namespace]; ok {
+		scopes = append(scopes, serviceScope(extraConfig(botdetector.Namespace)...))
+		details = append(details, v)
+	}
+	for i, e := range s.Endpoints {
+		if v, ok := e.Components[botdetector.Namespace]; ok {
+			scopes = append(scopes, endpointScope(i, extraConfig(botdetector.Namespace)...))
+			details = append(details, v)
+		}
+	}
+	return scopes, details
+}
+
+func hasInertBotDetector(s *Service) []Scope {
+	var res []Scope
+	scopes, details := botDetectors(s)
+	for i, v := range details {
+		if isInertBotDetector(v) {
+			res = append(res, scopes[i])
+		}
+	}
+	return res
+}
+
+func hasUncachedBotDetector(s *Service) []Scope {
+	var res []Scope
+	scopes, details := botDetectors(s)
+	for i, v := range details {
+		if len(v) > 3 && v[2] >= botDetectorHeavyPatterns && v[3] <= 0 {
+			res = append(res, append(scopes[i], "cache_size"))
+		}
+	}
+	return res
 }
 
-func hasBotdetectorDisabled(s *Service) bool {
-	_, ok := s.Components[botdetector.Namespace]
-	return !ok
+func hasInconsistentBotDetection(s *Service) []Scope {
+	if _, ok := s.Components[botdetector.Namespace]; ok {
+		return nil
+	}
+	var res []Scope
+	protected := 0
+	for i, e := range s.Endpoints {
+		if _, ok := e.Components[botdetector.Namespace]; ok {
+			protected++
+			continue
+		}
+		res = append(res, endpointScope(i, extraConfig(botdetector.Namespace)...))
+	}
+	if protected == 0 {
+		return nil
+	}
+	return res
 }
 
-func hasNoRatelimit(s *Service) bool {
+func hasNoRatelimit(s *Service) []Scope {
 	_, ok := s.Components[ratelimit.Namespace]
 	if ok {
-		return false
+		return nil
 	}
 	for _, e := range s.Endpoints {
 		_, ok := e.Components[ratelimit.Namespace]
 		if ok {
-			return false
+			return nil
 		}
 		_, ok = e.Components[ratelimitProxy.Namespace]
 		if ok {
-			return false
+			return nil
 		}
 		for _, b := range e.Backends {
 			_, ok := b.Components[ratelimitProxy.Namespace]
 			if ok {
-				return false
+				return nil
 			}
 		}
 	}
 
-	_, ok = s.Components["qos/ratelimit/service"]
+	_, ok = s.Components[ratelimitServiceNamespace]
 	if ok {
-		return false
+		return nil
 	}
 
 	serverPlugins, ok := s.Components[server.Namespace]
@@ -262,41 +542,230 @@ func hasNoRatelimit(s *Service) bool {
 		pluginsBitset := serverPlugins[0]
 		redisRateLimitBit := parseServerPlugin("redis-ratelimit")
 		if hasBit(pluginsBitset, redisRateLimitBit) {
-			return false
+			return nil
 		}
 	}
 
-	return true
+	return []Scope{serviceScope(extraConfig(ratelimit.Namespace)...)}
 }
 
-func hasNoCB(s *Service) bool {
+func hasNoCB(s *Service) []Scope {
 	for _, e := range s.Endpoints {
 		_, ok := e.Components[cb.Namespace]
 		if ok {
-			return false
+			return nil
 		}
 		for _, b := range e.Backends {
 			_, ok := b.Components[cb.Namespace]
 			if ok {
-				return false
+				return nil
 			}
 		}
 	}
-	return true
+	return []Scope{serviceScope("endpoints")}
 }
 
-func hasTimeoutBiggerThan(d int) func(*Service) bool {
-	return func(s *Service) bool {
-		for _, e := range s.Endpoints {
-			if e.Details[3] > d {
-				return true
+func hasTimeoutBiggerThan(d int) func(*Service) []Scope {
+	return func(s *Service) []Scope {
+		var res []Scope
+		for i, e := range s.Endpoints {
+			if len(e.Details) > EndpointDetailTimeout && e.Details[EndpointDetailTimeout] > d {
+				res = append(res, endpointScope(i, "timeout"))
 			}
 		}
-		return false
+		return res
+	}
+}
+
+// serverMaxHeaderBytes is the default limit of the size of the request headers of the
+// http server, applied when max_header_bytes is zero
+const serverMaxHeaderBytes = 1 << 20
+
+func hasNoReadHeaderTimeout(s *Service) []Scope {
+	if len(s.Details) <= ServiceDetailReadHeaderTimeout {
+		return nil
+	}
+	// the http server falls back to the read timeout when the read header one is not set
+	// (see the ReadHeaderTimeout field of net/http.Server)
+	if s.Details[ServiceDetailReadHeaderTimeout] > 0 || s.Details[ServiceDetailReadTimeout] > 0 {
+		return nil
+	}
+	return []Scope{serviceScope("read_header_timeout")}
+}
+
+func hasShortWriteTimeout(s *Service) []Scope {
+	writeTimeout := serviceDetail(s, ServiceDetailWriteTimeout)
+	if writeTimeout <= 0 {
+		return nil
+	}
+	for _, e := range s.Endpoints {
+		if len(e.Details) > EndpointDetailTimeout && e.Details[EndpointDetailTimeout] > writeTimeout {
+			return []Scope{serviceScope("write_timeout")}
+		}
+	}
+	return nil
+}
+
+func hasUnboundedIdleConnections(s *Service) []Scope {
+	if len(s.Details) <= ServiceDetailIdleTimeout {
+		return nil
+	}
+	// the http server falls back to the read timeout when the idle one is not set
+	// (see the IdleTimeout field of net/http.Server)
+	if s.Details[ServiceDetailIdleTimeout] > 0 || s.Details[ServiceDetailReadTimeout] > 0 {
+		return nil
 	}
+	return []Scope{serviceScope("idle_timeout")}
 }
 
-func hasNoMetrics(s *Service) bool {
+func hasLargeMaxHeaderBytes(s *Service) []Scope {
+	if serviceDetail(s, ServiceDetailMaxHeaderBytes) > serverMaxHeaderBytes {
+		return []Scope{serviceScope("max_header_bytes")}
+	}
+	return nil
+}
+
+// default values of the websocket component, applied when the parsed value is zero
+const (
+	wsDefaultBufferSize        = 1024
+	wsDefaultMessageBufferSize = 256
+	wsDefaultMaxMessageSize    = 512
+	wsDefaultPongWait          = 60000
+	wsDefaultPingPeriod        = 54000
+)
+
+const (
+	// wsMaxMessageSize is the largest message size, in bytes, considered bounded
+	wsMaxMessageSize = 1 << 20
+	// wsMaxConnectionMemory is the largest amount of memory, in bytes, a single
+	// connection can retain before it becomes a risk for a large number of clients
+	wsMaxConnectionMemory = 16 << 20
+)
+
+func valueOr(v, def int) int {
+	if v == 0 {
+		return def
+	}
+	return v
+}
+
+// websockets returns the index of the endpoints declaring the websocket component
+// along with its parsed details
+func websockets(s *Service) ([]int, [][]int) {
+	var idx []int
+	var details [][]int
+	for i, e := range s.Endpoints {
+		v, ok := e.Components["websocket"]
+		if !ok || len(v) < 11 {
+			continue
+		}
+		idx = append(idx, i)
+		details = append(details, v)
+	}
+	return idx, details
+}
+
+func wsScope(i int, path ...string) Scope {
+	return endpointScope(i, extraConfig("websocket", path...)...)
+}
+
+func hasUnboundedWebSocketMessages(s *Service) []Scope {
+	var res []Scope
+	idx, details := websockets(s)
+	for k, v := range details {
+		if v[4] > wsMaxMessageSize {
+			res = append(res, wsScope(idx[k], "max_message_size"))
+		}
+	}
+	return res
+}
+
+func hasWebSocketErrorDetails(s *Service) []Scope {
+	var res []Scope
+	idx, details := websockets(s)
+	for k, v := range details {
+		if hasBit(v[0], 2) {
+			res = append(res, wsScope(idx[k], "return_error_details"))
+		}
+	}
+	return res
+}
+
+func hasWebSocketPingAfterPong(s *Service) []Scope {
+	var res []Scope
+	idx, details := websockets(s)
+	for k, v := range details {
+		if valueOr(v[8], wsDefaultPingPeriod) >= valueOr(v[7], wsDefaultPongWait) {
+			res = append(res, wsScope(idx[k], "ping_period"))
+		}
+	}
+	return res
+}
+
+func hasLargeWebSocketBuffers(s *Service) []Scope {
+	var res []Scope
+	idx, details := websockets(s)
+	for k, v := range details {
+		memory := valueOr(v[1], wsDefaultBufferSize) + valueOr(v[2], wsDefaultBufferSize) +
+			valueOr(v[3], wsDefaultMessageBufferSize)*valueOr(v[4], wsDefaultMaxMessageSize)
+		if memory > wsMaxConnectionMemory {
+			res = append(res, wsScope(idx[k]))
+		}
+	}
+	return res
+}
+
+func hasUnlimitedWebSocketRetries(s *Service) []Scope {
+	var res []Scope
+	idx, details := websockets(s)
+	for k, v := range details {
+		if v[5] <= 0 {
+			res = append(res, wsScope(idx[k], "max_retries"))
+		}
+	}
+	return res
+}
+
+// authNamespaces are the components authenticating the clients of an endpoint
+var authNamespaces = []string{jose.ValidatorNamespace, "auth/basic", "auth/api-keys"}
+
+func isAuthenticatedEndpoint(e Endpoint) bool {
+	for _, ns := range authNamespaces {
+		if _, ok := e.Components[ns]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hasUnauthenticatedWebSocket(s *Service) []Scope {
+	var res []Scope
+	idx, _ := websockets(s)
+	for _, i := range idx {
+		if !isAuthenticatedEndpoint(s.Endpoints[i]) {
+			res = append(res, endpointScope(i, "extra_config"))
+		}
+	}
+	return res
+}
+
+func hasUnlimitedWebSocket(s *Service) []Scope {
+	for _, ns := range []string{ratelimit.Namespace, ratelimitServiceNamespace} {
+		if _, ok := s.Components[ns]; ok {
+			return nil
+		}
+	}
+	var res []Scope
+	idx, _ := websockets(s)
+	for _, i := range idx {
+		if _, ok := s.Endpoints[i].Components[ratelimit.Namespace]; !ok {
+			res = append(res, endpointScope(i, extraConfig(ratelimit.Namespace)...))
+		}
+	}
+	return res
+}
+
+func hasNoMetrics(s *Service) []Scope {
 	for _, k := range []string{
 		opencensus.Namespace,
 		metrics.Namespace,
@@ -305,13 +774,68 @@ func hasNoMetrics(s *Service) bool {
 		"telemetry/instana",
 	} {
 		if _, ok := s.Components[k]; ok {
-			return false
+			return nil
 		}
 	}
-	return true
+	otel, ok := s.Components["telemetry/opentelemetry"]
+	if ok && len(otel) >= 5 && otel[2]+otel[4] > 0 {
+		// OTLP metrics or prometheus enabled
+		return nil
+	}
+	return []Scope{serviceScope("extra_config")}
+}
+
+// otelMinReportingPeriod is the minimum period, in seconds, between metric reports
+// that does not overload the collectors
+const otelMinReportingPeriod = 10
+
+func otelScope(path ...string) Scope {
+	return serviceScope(extraConfig("telemetry/opentelemetry", path...)...)
+}
+
+func hasShortMetricReportingPeriod(s *Service) []Scope {
+	otel, ok := s.Components["telemetry/opentelemetry"]
+	// zero or negative values fall back to the default period of the exporters
+	if ok && len(otel) > 0 && otel[0] > 0 && otel[0] < otelMinReportingPeriod {
+		return []Scope{otelScope("metric_reporting_period")}
+	}
+	return nil
+}
+
+func hasFullTraceSampling(s *Service) []Scope {
+	otel, ok := s.Components["telemetry/opentelemetry"]
+	if ok && len(otel) > 1 && otel[1] >= 100 {
+		return []Scope{otelScope("trace_sample_rate")}
+	}
+	return nil
+}
+
+func hasInsecureOTLPExporter(s *Service) []Scope {
+	otel, ok := s.Components["telemetry/opentelemetry"]
+	if !ok || len(otel) < 7 {
+		return nil
+	}
+	var res []Scope
+	for i := 0; i < 31; i++ {
+		if hasBit(otel[6], i) {
+			res = append(res, otelScope("exporters", "otlp", strconv.Itoa(i), "host"))
+		}
+	}
+	return res
+}
+
+func hasAllOTELExportersDisabled(s *Service) []Scope {
+	otel, ok := s.Components["telemetry/opentelemetry"]
+	if !ok || len(otel) < 6 {
+		return nil
+	}
+	if otel[5] > 0 && otel[2]+otel[3]+otel[4] == 0 {
+		return []Scope{otelScope("exporters")}
+	}
+	return nil
 }
 
-func hasSeveralTelemetryComponents(s *Service) bool {
+func hasSeveralTelemetryComponents(s *Service) []Scope {
 	tot := 0
 	for _, k := range []string{
 		opencensus.Namespace,
@@ -330,10 +854,13 @@ func hasSeveralTelemetryComponents(s *Service) bool {
 		// OTL enabled metrics + prometheus
 		tot += otel[2] + otel[4]
 	}
-	return tot > 1
+	if tot > 1 {
+		return []Scope{serviceScope("extra_config")}
+	}
+	return nil
 }
 
-func hasNoTracing(s *Service) bool {
+func hasNoTracing(s *Service) []Scope {
 	_, ok1 := s.Components[opencensus.Namespace]
 	_, ok2 := s.Components["telemetry/newrelic"]
 	_, ok3 := s.Components["telemetry/instana"]
@@ -345,98 +872,752 @@ func hasNoTracing(s *Service) bool {
 			okOTEL = false
 		}
 	}
-	return !ok1 && !ok2 && !ok3 && !okOTEL
-}
-
-func hasDeprecatedInstana(s *Service) bool {
-	_, ok := s.Components["telemetry/instana"]
-	return ok
-}
-
-func hasDeprecatedGanalytics(s *Service) bool {
-	_, ok := s.Components["telemetry/ganalytics"]
-	return ok
+	if !ok1 && !ok2 && !ok3 && !okOTEL {
+		return []Scope{serviceScope("extra_config")}
+	}
+	return nil
 }
 
-func hasDeprecatedOpenCensus(s *Service) bool {
-	_, ok := s.Components[opencensus.Namespace]
-	return ok
+func hasServiceComponent(namespace string) func(*Service) []Scope {
+	return func(s *Service) []Scope {
+		if _, ok := s.Components[namespace]; ok {
+			return []Scope{serviceScope(extraConfig(namespace)...)}
+		}
+		return nil
+	}
 }
 
-func hasDeprecatedInflux(s *Service) bool {
-	_, ok := s.Components[influx.Namespace]
-	return ok
-}
+var (
+	hasDeprecatedInstana    = hasServiceComponent("telemetry/instana")
+	hasDeprecatedGanalytics = hasServiceComponent("telemetry/ganalytics")
+	hasDeprecatedOpenCensus = hasServiceComponent(opencensus.Namespace)
+	hasDeprecatedInflux     = hasServiceComponent(influx.Namespace)
+)
 
-func hasDeprecatedTLSPrivPubKey(s *Service) bool {
-	return hasBit(s.Details[0], ServiceTLSPrivPubKey)
+func hasDeprecatedTLSPrivPubKey(s *Service) []Scope {
+	if hasBit(s.Details[0], ServiceTLSPrivPubKey) {
+		return []Scope{serviceScope("tls")}
+	}
+	return nil
 }
 
-func hasNoLogging(s *Service) bool {
+func hasNoLogging(s *Service) []Scope {
 	_, ok1 := s.Components[gologging.Namespace]
 	_, ok2 := s.Components[gelf.Namespace]
 	_, ok3 := s.Components[logstash.Namespace]
-	return !ok1 && !ok2 && !ok3
-}
-
-func hasRestfulDisabled(s *Service) bool {
-	return hasBit(s.Details[0], ServiceDisableStrictREST)
+	if !ok1 && !ok2 && !ok3 {
+		return []Scope{serviceScope("extra_config")}
+	}
+	return nil
 }
 
-func hasDebugEnabled(s *Service) bool {
-	return hasBit(s.Details[0], ServiceDebug)
+func hasServiceFlag(flag int, path ...string) func(*Service) []Scope {
+	return func(s *Service) []Scope {
+		if hasBit(s.Details[0], flag) {
+			return []Scope{serviceScope(path...)}
+		}
+		return nil
+	}
 }
 
-func hasEchoEnabled(s *Service) bool {
-	return hasBit(s.Details[0], ServiceEcho)
-}
+var (
+	hasRestfulDisabled = hasServiceFlag(ServiceDisableStrictREST, "disable_rest")
+	hasDebugEnabled    = hasServiceFlag(ServiceDebug, "debug_endpoint")
+	hasEchoEnabled     = hasServiceFlag(ServiceEcho, "echo_endpoint")
+)
 
-func hasEndpointWithoutBackends(s *Service) bool {
-	for _, e := range s.Endpoints {
+func hasEndpointWithoutBackends(s *Service) []Scope {
+	var res []Scope
+	for i, e := range s.Endpoints {
 		if len(e.Backends) == 0 {
-			return true
+			res = append(res, endpointScope(i, "backend"))
 		}
 	}
-	return false
+	return res
 }
 
-func hasASingleBackendPerEndpoint(s *Service) bool {
+func hasASingleBackendPerEndpoint(s *Service) []Scope {
 	for _, e := range s.Endpoints {
 		if len(e.Backends) > 1 {
-			return false
+			return nil
 		}
 	}
-	return true
+	return []Scope{serviceScope("endpoints")}
 }
 
-func hasAllEndpointsAsNoop(s *Service) bool {
+func hasAllEndpointsAsNoop(s *Service) []Scope {
 	for _, e := range s.Endpoints {
 		if !hasBit(e.Details[0], EncodingNOOP) {
+			return nil
+		}
+	}
+	return []Scope{serviceScope("endpoints")}
+}
+
+func hasSequentialStart(s *Service) []Scope {
+	if hasBit(s.Details[0], ServiceSequentialStart) && len(s.Agents) >= 10 {
+		return []Scope{serviceScope("sequential_start")}
+	}
+	return nil
+}
+
+func hasEmptyGRPCServer(s *Service) []Scope {
+	if len(s.Components["grpc"]) > 0 && s.Components["grpc"][0] == 0 {
+		return []Scope{serviceScope(extraConfig("grpc", "server", "services")...)}
+	}
+	return nil
+}
+
+func hasUnlimitedCache(s *Service) []Scope {
+	var res []Scope
+	scopes, backends := allBackends(s)
+	for i, b := range backends {
+		cache, ok := b.Components[httpcache.Namespace]
+		if !ok || len(cache) == 0 {
+			continue
+		}
+		if !hasBit(cache[0], HTTPCacheMaxItems) || !hasBit(cache[0], HTTPCacheMaxSize) {
+			res = append(res, append(scopes[i], extraConfig(httpcache.Namespace)...))
+		}
+	}
+	return res
+}
+
+// unsafeMethodMaxCacheTTL is the longest cache_ttl, in milliseconds, tolerated on
+// endpoints with methods that change the state of the backends
+const unsafeMethodMaxCacheTTL = 60000
+
+// hasCredentials checks if the responses of the endpoint depend on the identity of the
+// client, as it is authenticated or forwards credential headers to the backends
+func hasCredentials(e Endpoint) bool {
+	if isAuthenticatedEndpoint(e) {
+		return true
+	}
+	return len(e.Details) > EndpointDetailCredentialHeaders && e.Details[EndpointDetailCredentialHeaders] != 0
+}
+
+func hasSharedCacheOnAuthenticatedEndpoint(s *Service) []Scope {
+	var res []Scope
+	for i, e := range s.Endpoints {
+		if !hasCredentials(e) {
+			continue
+		}
+		for j, b := range e.Backends {
+			cache, ok := b.Components[httpcache.Namespace]
+			if ok && len(cache) > 0 && hasBit(cache[0], HTTPCacheShared) {
+				res = append(res, endpointBackendScope(i, j, extraConfig(httpcache.Namespace, "shared")...))
+			}
+		}
+	}
+	return res
+}
+
+func hasLongCacheTTLOnUnsafeMethod(s *Service) []Scope {
+	safeMethods := 1<<MethodGET | 1<<MethodHEAD | 1<<MethodOPTIONS
+	var res []Scope
+	for i, e := range s.Endpoints {
+		if len(e.Details) <= EndpointDetailCacheTTL || e.Details[EndpointDetailMethod]&safeMethods != 0 {
+			continue
+		}
+		if e.Details[EndpointDetailCacheTTL] > unsafeMethodMaxCacheTTL {
+			res = append(res, endpointScope(i, "cache_ttl"))
+		}
+	}
+	return res
+}
+
+func hasCacheOnNoopEndpoint(s *Service) []Scope {
+	var res []Scope
+	for i, e := range s.Endpoints {
+		if len(e.Details) == 0 || !hasBit(e.Details[0], EncodingNOOP) {
+			continue
+		}
+		if len(e.Details) > EndpointDetailCacheTTL && e.Details[EndpointDetailCacheTTL] > 0 {
+			res = append(res, endpointScope(i, "cache_ttl"))
+		}
+		for j, b := range e.Backends {
+			if _, ok := b.Components[httpcache.Namespace]; ok {
+				res = append(res, endpointBackendScope(i, j, extraConfig(httpcache.Namespace)...))
+			}
+		}
+	}
+	return res
+}
+
+const (
+	// aiMaxTimeout is the longest timeout, in milliseconds, considered reasonable for
+	// an endpoint consuming an LLM or MCP tools
+	aiMaxTimeout = 120000
+	// mcpMaxTools is the number of tools of a single MCP server from which the models
+	// start to choose the wrong tools and the exposed surface is hard to review
+	mcpMaxTools = 20
+)
+
+// aiEndpoints returns the index of the endpoints with an ai/llm backend or an ai/mcp
+// server
+func aiEndpoints(s *Service) []int {
+	var res []int
+	for i, e := range s.Endpoints {
+		if _, ok := e.Components["ai/mcp"]; ok {
+			res = append(res, i)
+			continue
+		}
+		for _, b := range e.Backends {
+			if _, ok := b.Components["ai/llm"]; ok {
+				res = append(res, i)
+				break
+			}
+		}
+	}
+	return res
+}
+
+func hasUnauthenticatedAIEndpoint(s *Service) []Scope {
+	var res []Scope
+	for _, i := range aiEndpoints(s) {
+		if !isAuthenticatedEndpoint(s.Endpoints[i]) {
+			res = append(res, endpointScope(i, "extra_config"))
+		}
+	}
+	return res
+}
+
+func hasUnlimitedAIEndpoint(s *Service) []Scope {
+	for _, ns := range []string{ratelimit.Namespace, ratelimitServiceNamespace} {
+		if _, ok := s.Components[ns]; ok {
+			return nil
+		}
+	}
+	var res []Scope
+	for _, i := range aiEndpoints(s) {
+		e := s.Endpoints[i]
+		limited := false
+		for _, ns := range []string{ratelimit.Namespace, "governance/quota"} {
+			if _, ok := e.Components[ns]; ok {
+				limited = true
+			}
+		}
+		for _, b := range e.Backends {
+			for _, ns := range []string{ratelimitProxy.Namespace, "governance/quota"} {
+				if _, ok := b.Components[ns]; ok {
+					limited = true
+				}
+			}
+		}
+		if !limited {
+			res = append(res, endpointScope(i, extraConfig(ratelimit.Namespace)...))
+		}
+	}
+	return res
+}
+
+func hasLongAITimeout(s *Service) []Scope {
+	var res []Scope
+	for _, i := range aiEndpoints(s) {
+		if e := s.Endpoints[i]; len(e.Details) > EndpointDetailTimeout && e.Details[EndpointDetailTimeout] > aiMaxTimeout {
+			res = append(res, endpointScope(i, "timeout"))
+		}
+	}
+	return res
+}
+
+func hasOutdatedLLMVersion(s *Service) []Scope {
+	var res []Scope
+	for i, e := range s.Endpoints {
+		for j, b := range e.Backends {
+			v, ok := b.Components["ai/llm"]
+			if !ok || len(v) < 5 || v[4] == 0 {
+				continue
+			}
+			for k, pr := range AiProviders {
+				if hasBit(v[4], k) {
+					res = append(res, endpointBackendScope(i, j, extraConfig("ai/llm", pr[0])...))
+				}
+			}
+		}
+	}
+	return res
+}
+
+func hasLargeMCPServer(s *Service) []Scope {
+	v, ok := s.Components["ai/mcp"]
+	if ok && len(v) > 2 && v[2] > mcpMaxTools {
+		return []Scope{serviceScope(extraConfig("ai/mcp", "servers")...)}
+	}
+	return nil
+}
+
+// luaMaxInlineSize is the size, in bytes, of the inline Lua code from which it is
+// better maintained and reviewed in source files
+const luaMaxInlineSize = 1024
+
+// luaScripts returns the scopes of every Lua component of the service, from the router
+// to the backends, along with their parsed details
+func luaScripts(s *Service) ([]Scope, [][]int) {
+	var scopes []Scope
+	var details [][]int
+	add := func(c Component, scope func(path ...string) Scope) {
+		for _, ns := range []string{luarouter.Namespace, luaproxy.ProxyNamespace, luaproxy.BackendNamespace} {
+			if v, ok := c[ns]; ok && len(v) > 0 {
+				scopes = append(scopes, scope(extraConfig(ns)...))
+				details = append(details, v)
+			}
+		}
+	}
+	add(s.Components, serviceScope)
+	for i, e := range s.Endpoints {
+		add(e.Components, func(path ...string) Scope { return endpointScope(i, path...) })
+		for j, b := range e.Backends {
+			add(b.Components, func(path ...string) Scope { return endpointBackendScope(i, j, path...) })
+		}
+	}
+	for i, a := range s.Agents {
+		for j, b := range a.Backends {
+			add(b.Components, func(path ...string) Scope { return agentBackendScope(i, j, path...) })
+		}
+	}
+	return scopes, details
+}
+
+func hasLuaFlag(flag int, field string) func(*Service) []Scope {
+	return func(s *Service) []Scope {
+		var res []Scope
+		scopes, details := luaScripts(s)
+		for i, v := range details {
+			if hasBit(v[0], flag) {
+				res = append(res, append(scopes[i], field))
+			}
+		}
+		return res
+	}
+}
+
+var (
+	hasLuaOpenLibs = hasLuaFlag(LuaAllowOpenLibs, "allow_open_libs")
+	hasLuaLive     = hasLuaFlag(LuaLive, "live")
+)
+
+func hasLargeInlineLua(s *Service) []Scope {
+	var res []Scope
+	scopes, details := luaScripts(s)
+	for i, v := range details {
+		if len(v) > 2 && v[2] > luaMaxInlineSize {
+			res = append(res, scopes[i])
+		}
+	}
+	return res
+}
+
+func hasLuaInAllLayers(s *Service) []Scope {
+	_, globalRouter := s.Components[luarouter.Namespace]
+	var res []Scope
+	for i, e := range s.Endpoints {
+		_, router := e.Components[luarouter.Namespace]
+		if _, ok := e.Components[luaproxy.ProxyNamespace]; !ok || !(router || globalRouter) {
+			continue
+		}
+		for _, b := range e.Backends {
+			if _, ok := b.Components[luaproxy.BackendNamespace]; ok {
+				res = append(res, endpointScope(i, "extra_config"))
+				break
+			}
+		}
+	}
+	return res
+}
+
+// responseSchemaMaxSize is the size, in bytes, of the serialized response schema from
+// which the validation noticeably slows down every response
+const responseSchemaMaxSize = 10 * 1024
+
+const responseSchemaNamespace = "validation/response-json-schema"
+
+// responseSchemas returns the scopes of every response JSON schema validation, at the
+// endpoint and backend levels, along with their parsed details
+func responseSchemas(s *Service) ([]Scope, [][]int) {
+	var scopes []Scope
+	var details [][]int
+	for i, e := range s.Endpoints {
+		if v, ok := e.Components[responseSchemaNamespace]; ok && len(v) > 3 {
+			scopes = append(scopes, endpointScope(i, extraConfig(responseSchemaNamespace)...))
+			details = append(details, v)
+		}
+		for j, b := range e.Backends {
+			if v, ok := b.Components[responseSchemaNamespace]; ok && len(v) > 3 {
+				scopes = append(scopes, endpointBackendScope(i, j, extraConfig(responseSchemaNamespace)...))
+				details = append(details, v)
+			}
+		}
+	}
+	return scopes, details
+}
+
+func hasResponseSchemaErrorWithoutContentType(s *Service) []Scope {
+	var res []Scope
+	scopes, details := responseSchemas(s)
+	for i, v := range details {
+		if v[1] == 1 && v[3] == 0 {
+			res = append(res, append(scopes[i], "error", "content_type"))
+		}
+	}
+	return res
+}
+
+func hasResponseSchemaNonErrorStatus(s *Service) []Scope {
+	var res []Scope
+	scopes, details := responseSchemas(s)
+	for i, v := range details {
+		if v[2] != 0 && (v[2] < 400 || v[2] > 599) {
+			res = append(res, append(scopes[i], "error", "status"))
+		}
+	}
+	return res
+}
+
+func hasLargeResponseSchema(s *Service) []Scope {
+	var res []Scope
+	scopes, details := responseSchemas(s)
+	for i, v := range details {
+		if v[0] > responseSchemaMaxSize {
+			res = append(res, append(scopes[i], "schema"))
+		}
+	}
+	return res
+}
+
+func hasDuplicatedResponseSchemaValidation(s *Service) []Scope {
+	id := parseRespReqPlugin("response-schema-validator")
+	isDeprecated := func(c Component) bool {
+		v, ok := c[plugin.Namespace]
+		return ok && len(v) > 0 && hasBit(v[0], id)
+	}
+	var res []Scope
+	for i, e := range s.Endpoints {
+		_, validates := e.Components[responseSchemaNamespace]
+		deprecated := isDeprecated(e.Components)
+		for _, b := range e.Backends {
+			if _, ok := b.Components[responseSchemaNamespace]; ok {
+				validates = true
+			}
+			deprecated = deprecated || isDeprecated(b.Components)
+		}
+		if validates && deprecated {
+			res = append(res, endpointScope(i, "extra_config"))
+		}
+	}
+	return res
+}
+
+const (
+	// modifierMaxRegexps is the number of regexp modifiers per endpoint from which
+	// the response manipulation becomes noticeable
+	modifierMaxRegexps = 5
+	// modifierMaxFanOutChain is the number of modifiers from which the chain is too
+	// long for an endpoint with modifierFanOut backends or more
+	modifierMaxFanOutChain = 10
+	modifierFanOut         = 3
+)
+
+// responseBodyModifiers returns the scopes of the response body modifiers of the endpoint
+// and its backends, along with their parsed details
+func responseBodyModifiers(i int, e Endpoint) ([]Scope, [][]int) {
+	var scopes []Scope
+	var details [][]int
+	if v, ok := e.Components[responseBodyModifierNamespace]; ok && len(v) > 1 {
+		scopes = append(scopes, endpointScope(i, extraConfig(responseBodyModifierNamespace)...))
+		details = append(details, v)
+	}
+	for j, b := range e.Backends {
+		if v, ok := b.Components[responseBodyModifierNamespace]; ok && len(v) > 1 {
+			scopes = append(scopes, endpointBackendScope(i, j, extraConfig(responseBodyModifierNamespace)...))
+			details = append(details, v)
+		}
+	}
+	return scopes, details
+}
+
+func hasManyRegexpModifiers(s *Service) []Scope {
+	var res []Scope
+	for i, e := range s.Endpoints {
+		scopes, details := responseBodyModifiers(i, e)
+		regexps := 0
+		for _, v := range details {
+			regexps += v[1]
+		}
+		if regexps > modifierMaxRegexps {
+			res = append(res, scopes...)
+		}
+	}
+	return res
+}
+
+func hasModifiersOnNoopEndpoint(s *Service) []Scope {
+	var res []Scope
+	for i, e := range s.Endpoints {
+		if len(e.Details) == 0 || !hasBit(e.Details[0], EncodingNOOP) {
+			continue
+		}
+		scopes, _ := responseBodyModifiers(i, e)
+		res = append(res, scopes...)
+	}
+	return res
+}
+
+func hasLongModifierChainOnFanOut(s *Service) []Scope {
+	var res []Scope
+	for i, e := range s.Endpoints {
+		if len(e.Backends) < modifierFanOut {
+			continue
+		}
+		scopes, details := responseBodyModifiers(i, e)
+		total := 0
+		for _, v := range details {
+			total += v[0]
+		}
+		if total > modifierMaxFanOutChain {
+			res = append(res, scopes...)
+		}
+	}
+	return res
+}
+
+// hasPlaintextBackend reports the backends reached in clear text outside the loopback
+// interface and the private ranges, host names included, when the gateway terminates
+// TLS, as the encryption of the clients is lost on the way to the backends
+func hasPlaintextBackend(s *Service) []Scope {
+	if !isServerTLSEnabled(s) {
+		return nil
+	}
+	return plaintextBackends(s)
+}
+
+// allBackends returns the backends of the endpoints and the async agents, along with
+// their scopes
+func allBackends(s *Service) ([]Scope, []Backend) {
+	var scopes []Scope
+	var backends []Backend
+	for i, e := range s.Endpoints {
+		for j, b := range e.Backends {
+			scopes = append(scopes, endpointBackendScope(i, j))
+			backends = append(backends, b)
+		}
+	}
+	for i, a := range s.Agents {
+		for j, b := range a.Backends {
+			scopes = append(scopes, agentBackendScope(i, j))
+			backends = append(backends, b)
+		}
+	}
+	return scopes, backends
+}
+
+func hasBackendHosts(match func(hosts, sd, count int) bool) func(*Service) []Scope {
+	return func(s *Service) []Scope {
+		var res []Scope
+		scopes, backends := allBackends(s)
+		for i, b := range backends {
+			if len(b.Details) <= BackendDetailHostCount {
+				continue
+			}
+			if match(b.Details[BackendDetailHosts], b.Details[BackendDetailSD], b.Details[BackendDetailHostCount]) {
+				res = append(res, append(scopes[i], "host"))
+			}
+		}
+		return res
+	}
+}
+
+var (
+	plaintextBackends = hasBackendHosts(func(hosts, _, _ int) bool {
+		return hasBit(hosts, BackendHostPlainRemote)
+	})
+	hasMixedBackendSchemes = hasBackendHosts(func(hosts, _, _ int) bool {
+		return hasBit(hosts, BackendHostHTTP) && hasBit(hosts, BackendHostHTTPS)
+	})
+	hasLoopbackBackend = hasBackendHosts(func(hosts, _, _ int) bool {
+		return hasBit(hosts, BackendHostLoopback)
+	})
+	hasSingleHostBackend = hasBackendHosts(func(hosts, sd, count int) bool {
+		return sd == BackendSDStatic && count == 1 && !hasBit(hosts, BackendHostLoopback)
+	})
+)
+
+// endpointRoute returns the method and the segments of the route of the endpoint
+func endpointRoute(e Endpoint) (int, []int, bool) {
+	if len(e.Details) < EndpointDetailRoute {
+		return 0, nil, false
+	}
+	return e.Details[EndpointDetailMethod], e.Details[EndpointDetailRoute:], true
+}
+
+func routeSegmentKind(segment int) int {
+	return segment & (1<<routeSegmentKindBits - 1)
+}
+
+// sameRouteSegment checks if both segments match the same requests, so parameters
+// with different names are considered equal
+func sameRouteSegment(a, b int) bool {
+	kind := routeSegmentKind(a)
+	return kind == routeSegmentKind(b) && (kind != RouteSegmentLiteral || a == b)
+}
+
+func sameRoutePrefix(a, b []int) bool {
+	if len(a) > len(b) {
+		return false
+	}
+	for k := range a {
+		if !sameRouteSegment(a[k], b[k]) {
 			return false
 		}
 	}
 	return true
 }
 
-func hasSequentialStart(s *Service) bool {
-	return hasBit(s.Details[0], ServiceSequentialStart) && len(s.Agents) >= 10
+func isWildcardRoute(segments []int) bool {
+	return slices.ContainsFunc(segments, func(segment int) bool {
+		return routeSegmentKind(segment) == RouteSegmentWildcard
+	})
 }
 
-func hasEmptyGRPCServer(s *Service) bool {
-	return len(s.Components["grpc"]) > 0 && s.Components["grpc"][0] == 0
+func hasDuplicatedRoutes(s *Service) []Scope {
+	var res []Scope
+	for j, e := range s.Endpoints {
+		method, route, ok := endpointRoute(e)
+		if !ok {
+			continue
+		}
+		for _, other := range s.Endpoints[:j] {
+			otherMethod, otherRoute, ok := endpointRoute(other)
+			if ok && method == otherMethod && len(route) == len(otherRoute) && sameRoutePrefix(otherRoute, route) {
+				res = append(res, endpointScope(j, "endpoint"))
+				break
+			}
+		}
+	}
+	return res
 }
 
-func hasUnlimitedCache(s *Service) bool {
-	for _, e := range s.Endpoints {
-		for _, b := range e.Backends {
-			cache, ok := b.Components[httpcache.Namespace]
-			if !ok {
+func hasRouteParamConflicts(s *Service) []Scope {
+	var res []Scope
+	for j, e := range s.Endpoints {
+		method, route, ok := endpointRoute(e)
+		if !ok {
+			continue
+		}
+		for _, other := range s.Endpoints[:j] {
+			otherMethod, otherRoute, ok := endpointRoute(other)
+			if !ok || method != otherMethod {
 				continue
 			}
-			if !hasBit(cache[0], 1) || !hasBit(cache[0], 2) {
-				return true
+			k := 0
+			for k < len(route) && k < len(otherRoute) && route[k] == otherRoute[k] {
+				k++
+			}
+			if k < len(route) && k < len(otherRoute) && routeSegmentKind(route[k]) == RouteSegmentParam && sameRouteSegment(route[k], otherRoute[k]) {
+				res = append(res, endpointScope(j, "endpoint"))
+				break
 			}
 		}
 	}
-	return false
+	return res
+}
+
+func hasRoutesShadowedByWildcard(s *Service) []Scope {
+	var res []Scope
+	for j, e := range s.Endpoints {
+		method, route, ok := endpointRoute(e)
+		if !ok || isWildcardRoute(route) || isCatchAllEndpoint(e) {
+			continue
+		}
+		for _, other := range s.Endpoints {
+			otherMethod, otherRoute, ok := endpointRoute(other)
+			if !ok || method != otherMethod || len(otherRoute) == 0 {
+				continue
+			}
+			prefix := otherRoute[:len(otherRoute)-1]
+			if routeSegmentKind(otherRoute[len(otherRoute)-1]) == RouteSegmentWildcard && len(route) > len(prefix) && sameRoutePrefix(prefix, route) {
+				res = append(res, endpointScope(j, "endpoint"))
+				break
+			}
+		}
+	}
+	return res
+}
+
+func isCatchAllEndpoint(e Endpoint) bool {
+	return len(e.Details) > EndpointDetailWildcards && hasBit(e.Details[EndpointDetailWildcards], BitEndpointCatchAll)
+}
+
+// routerRatelimits returns the scopes of the rate limits of the service and the endpoints,
+// along with their parsed details
+func routerRatelimits(s *Service) ([]Scope, [][]int) {
+	var scopes []Scope
+	var details [][]int
+	for _, ns := range []string{ratelimit.Namespace, ratelimitServiceNamespace} {
+		if v, ok := s.Components[ns]; ok && len(v) > 4 {
+			scopes = append(scopes, serviceScope(extraConfig(ns)...))
+			details = append(details, v)
+		}
+	}
+	for i, e := range s.Endpoints {
+		if v, ok := e.Components[ratelimit.Namespace]; ok && len(v) > 4 {
+			scopes = append(scopes, endpointScope(i, extraConfig(ratelimit.Namespace)...))
+			details = append(details, v)
+		}
+	}
+	return scopes, details
+}
+
+func hasClientStrategy(flags int) bool {
+	return hasBit(flags, RatelimitStrategyIP) || hasBit(flags, RatelimitStrategyHeader) || hasBit(flags, RatelimitStrategyParam)
+}
+
+func hasUnthrottledWriteEndpoint(s *Service) []Scope {
+	safeMethods := 1<<MethodGET | 1<<MethodHEAD | 1<<MethodOPTIONS
+	var res []Scope
+	for i, e := range s.Endpoints {
+		if len(e.Details) <= EndpointDetailMethod || e.Details[EndpointDetailMethod]&safeMethods != 0 {
+			continue
+		}
+		if v, ok := e.Components[ratelimit.Namespace]; ok && len(v) > 0 {
+			if hasBit(v[0], RatelimitMaxRate) || (hasBit(v[0], RatelimitClientMaxRate) && hasClientStrategy(v[0])) {
+				continue
+			}
+		}
+		res = append(res, endpointScope(i, extraConfig(ratelimit.Namespace)...))
+	}
+	return res
+}
+
+func hasClientRatelimitWithoutStrategy(s *Service) []Scope {
+	var res []Scope
+	scopes, details := routerRatelimits(s)
+	for i, v := range details {
+		if hasBit(v[0], RatelimitClientMaxRate) && !hasClientStrategy(v[0]) {
+			res = append(res, append(scopes[i], "strategy"))
+		}
+	}
+	return res
+}
+
+func hasClientRatelimitWithoutKey(s *Service) []Scope {
+	var res []Scope
+	scopes, details := routerRatelimits(s)
+	for i, v := range details {
+		if (hasBit(v[0], RatelimitStrategyHeader) || hasBit(v[0], RatelimitStrategyParam)) && !hasBit(v[0], RatelimitKey) {
+			res = append(res, append(scopes[i], "key"))
+		}
+	}
+	return res
+}
+
+func hasClientRateAboveMaxRate(s *Service) []Scope {
+	var res []Scope
+	scopes, details := routerRatelimits(s)
+	for i, v := range details {
+		if hasBit(v[0], RatelimitMaxRate) && hasBit(v[0], RatelimitClientMaxRate) && v[3] > v[1] {
+			res = append(res, append(scopes[i], "client_max_rate"))
+		}
+	}
+	return res
 }
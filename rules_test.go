@@ -1,301 +1,1179 @@
 package audit
 
 import (
+	"crypto/tls"
+	"reflect"
+	"strings"
 	"testing"
 
+	bf "github.com/krakend/bloomfilter/v2/krakend"
 	botdetector "github.com/krakend/krakend-botdetector/v2/krakend"
 	cb "github.com/krakend/krakend-circuitbreaker/v3/gobreaker"
 	cors "github.com/krakend/krakend-cors/v2"
 	gelf "github.com/krakend/krakend-gelf/v2"
 	gologging "github.com/krakend/krakend-gologging/v2"
+	httpcache "github.com/krakend/krakend-httpcache/v2"
 	httpsecure "github.com/krakend/krakend-httpsecure/v2"
 	jose "github.com/krakend/krakend-jose/v2"
 	logstash "github.com/krakend/krakend-logstash/v2"
+	luaproxy "github.com/krakend/krakend-lua/v2/proxy"
+	luarouter "github.com/krakend/krakend-lua/v2/router"
 	metrics "github.com/krakend/krakend-metrics/v2"
 	opencensus "github.com/krakend/krakend-opencensus/v2"
 	ratelimitProxy "github.com/krakend/krakend-ratelimit/v3/proxy"
 	ratelimit "github.com/krakend/krakend-ratelimit/v3/router"
+	"github.com/luraproject/lura/v2/config"
+	"github.com/luraproject/lura/v2/proxy/plugin"
 	router "github.com/luraproject/lura/v2/router/gin"
 	server "github.com/luraproject/lura/v2/transport/http/server/plugin"
 )
 
 func Test_hasBasicAuth(t *testing.T) {
-	if !hasBasicAuth(&Service{Components: Component{server.Namespace: []int{4}}}) {
+	if len(hasBasicAuth(&Service{Components: Component{server.Namespace: []int{4}}})) == 0 {
 		t.Error("false negative")
 	}
 
-	if hasBasicAuth(&Service{Components: Component{}}) {
+	if len(hasBasicAuth(&Service{Components: Component{}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if hasBasicAuth(&Service{Components: Component{server.Namespace: []int{0}}}) {
+	if len(hasBasicAuth(&Service{Components: Component{server.Namespace: []int{0}}})) > 0 {
 		t.Error("false positive")
 	}
 }
 
 func Test_hasApiKeys(t *testing.T) {
-	if !hasApiKeys(&Service{Components: Component{"auth/api-keys": []int{}}}) {
+	if len(hasApiKeys(&Service{Components: Component{"auth/api-keys": []int{}}})) == 0 {
 		t.Error("false negative")
 	}
 
-	if hasApiKeys(&Service{Components: Component{}}) {
+	if len(hasApiKeys(&Service{Components: Component{}})) > 0 {
 		t.Error("false positive")
 	}
 }
 
 func Test_hasNoJWT(t *testing.T) {
-	if hasNoJWT(&Service{Endpoints: []Endpoint{{Components: Component{jose.ValidatorNamespace: []int{}}}}}) {
+	if len(hasNoJWT(&Service{Endpoints: []Endpoint{{Components: Component{jose.ValidatorNamespace: []int{}}}}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasNoJWT(&Service{Components: Component{}}) {
+	if len(hasNoJWT(&Service{Components: Component{}})) == 0 {
 		t.Error("false negative")
 	}
 }
 
-func Test_hasInsecureConnections(t *testing.T) {
-	if hasInsecureConnections(&Service{Details: []int{2}}) {
+func Test_hasRevocation(t *testing.T) {
+	jwt := []Endpoint{{Components: Component{jose.ValidatorNamespace: []int{}}}}
+	if len(hasNoRevocation(&Service{Endpoints: jwt, Components: Component{}})) == 0 {
+		t.Error("false negative")
+	}
+
+	if len(hasNoRevocation(&Service{Endpoints: jwt, Components: Component{bf.Namespace: []int{1, 1, 0}}})) > 0 {
+		t.Error("false positive")
+	}
+
+	if len(hasNoRevocation(&Service{Components: Component{}})) > 0 {
+		t.Error("false positive")
+	}
+
+	if len(hasRevocationWithoutTokenKeys(&Service{Components: Component{bf.Namespace: []int{1, 0, 0}}})) == 0 {
+		t.Error("false negative")
+	}
+
+	if len(hasRevocationWithoutTokenKeys(&Service{Components: Component{bf.Namespace: []int{1, 2, 0}}})) > 0 {
+		t.Error("false positive")
+	}
+
+	if len(hasRevocationWithoutJWT(&Service{Components: Component{bf.Namespace: []int{1, 1, 0}}})) == 0 {
+		t.Error("false negative")
+	}
+
+	if len(hasRevocationWithoutJWT(&Service{Endpoints: jwt, Components: Component{bf.Namespace: []int{1, 1, 0}}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasInsecureConnections(&Service{Details: []int{24}}) {
+	if len(hasRevocationNonOptimalHash(&Service{Components: Component{bf.Namespace: []int{0, 1, 0}}})) == 0 {
 		t.Error("false negative")
 	}
+
+	if len(hasRevocationNonOptimalHash(&Service{Components: Component{bf.Namespace: []int{1, 1, 0}}})) > 0 {
+		t.Error("false positive")
+	}
+}
+
+func TestRun_revoker(t *testing.T) {
+	cfg, err := config.NewParser().Parse("./tests/revoker.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	cfg.Normalize()
+
+	result, err := Run(&cfg, IncludeRules("1.2.*"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(result.Recommendations) != 2 {
+		t.Errorf("unexpected recommendations: %+v", result.Recommendations)
+		return
+	}
+	for i, rule := range []string{"1.2.1", "1.2.4"} {
+		if result.Recommendations[i].Rule != rule {
+			t.Errorf("unexpected rule at %d. have: %s, want: %s", i, result.Recommendations[i].Rule, rule)
+		}
+	}
+}
+
+func Test_hasInsecureConnections(t *testing.T) {
+	if len(hasInsecureConnections(&Service{Details: []int{2}})) > 0 {
+		t.Error("false positive")
+	}
+
+	if scopes := hasInsecureConnections(&Service{Details: []int{24}}); len(scopes) != 1 || scopes[0].Pointer() != "/allow_insecure_connections" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	details := []int{1<<ServiceAllowInsecureConnections | 1<<ServiceClientTLSAllowInsecureConnections}
+	if scopes := hasInsecureConnections(&Service{Details: details}); len(scopes) != 1 || scopes[0].Pointer() != "/client_tls/allow_insecure_connections" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+}
+
+func Test_hasBackendInsecureConnections(t *testing.T) {
+	insecure := Component{"backend/http/client": []int{1 << BackendComponentHTTPClientAllowInsecureConnections}}
+	s := &Service{
+		Endpoints: []Endpoint{{Backends: []Backend{{Components: Component{}}, {Components: insecure}}}},
+		Agents:    []Agent{{Backends: []Backend{{Components: insecure}}}},
+	}
+	scopes := hasBackendInsecureConnections(s)
+	if len(scopes) != 2 ||
+		scopes[0].Pointer() != "/endpoints/0/backend/1/extra_config/backend~1http~1client" ||
+		scopes[1].Pointer() != "/async_agent/0/backend/0/extra_config/backend~1http~1client" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
 }
 
 func Test_hasNoTLS(t *testing.T) {
-	if hasNoTLS(&Service{Details: []int{1 << 5}}) {
+	if len(hasNoTLS(&Service{Details: []int{1 << 5}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasNoTLS(&Service{Details: []int{24}}) {
+	if len(hasNoTLS(&Service{Details: []int{24}})) == 0 {
 		t.Error("false negative")
 	}
 }
 
 func Test_hasTLSDisabled(t *testing.T) {
-	if hasTLSDisabled(&Service{Details: []int{1 << 6}}) {
+	if len(hasTLSDisabled(&Service{Details: []int{1 << 6}})) > 0 {
+		t.Error("false positive")
+	}
+	if len(hasTLSDisabled(&Service{Details: []int{1<<5 + 1<<6}})) > 0 {
+		t.Error("false positive")
+	}
+
+	if len(hasTLSDisabled(&Service{Details: []int{1 << 5}})) == 0 {
+		t.Error("false negative")
+	}
+}
+
+func Test_hasOutdatedTLSVersion(t *testing.T) {
+	enabled := 1<<ServiceHasTLS | 1<<ServiceTLSEnabled
+	if len(hasOutdatedTLSVersion(&Service{Details: []int{enabled, tls.VersionTLS11}})) == 0 {
+		t.Error("false negative")
+	}
+
+	if len(hasOutdatedTLSVersion(&Service{Details: []int{enabled, tls.VersionTLS12}})) > 0 {
+		t.Error("false positive")
+	}
+
+	if len(hasOutdatedTLSVersion(&Service{Details: []int{1 << ServiceHasTLS, tls.VersionTLS10}})) > 0 {
+		t.Error("false positive")
+	}
+
+	details := make([]int, ServiceDetailClientTLSCerts+1)
+	details[ServiceDetailClientTLSMinVersion] = tls.VersionTLS10
+	scopes := hasOutdatedTLSVersion(&Service{
+		Details: details,
+		Endpoints: []Endpoint{{Backends: []Backend{
+			{Components: Component{"backend/http/client": []int{1, tls.VersionTLS11, 0}}},
+			{Components: Component{"backend/http/client": []int{1, 0, 0}}},
+		}}},
+	})
+	if len(scopes) != 2 {
+		t.Errorf("unexpected scopes: %v", scopes)
+		return
+	}
+	if p := scopes[0].Pointer(); p != "/client_tls/min_version" {
+		t.Errorf("unexpected pointer: %s", p)
+	}
+	if p := scopes[1].Pointer(); p != "/endpoints/0/backend/0/extra_config/backend~1http~1client/client_tls/min_version" {
+		t.Errorf("unexpected pointer: %s", p)
+	}
+}
+
+func Test_hasTLSCipherSuites(t *testing.T) {
+	enabled := 1<<ServiceHasTLS | 1<<ServiceTLSEnabled
+	weak := 1<<TLSCipherSuitesCustom | 1<<TLSCipherSuitesInsecure | 1<<TLSCipherSuitesCBCOnly
+	if len(hasInsecureCipherSuites(&Service{Details: []int{enabled, tls.VersionTLS12, 0, weak}})) == 0 {
+		t.Error("false negative")
+	}
+
+	if len(hasCBCOnlyCipherSuites(&Service{Details: []int{enabled, tls.VersionTLS12, 0, weak}})) == 0 {
+		t.Error("false negative")
+	}
+
+	if len(hasInsecureCipherSuites(&Service{Details: []int{enabled, tls.VersionTLS13, 0, weak}})) > 0 {
 		t.Error("false positive")
 	}
-	if hasTLSDisabled(&Service{Details: []int{1<<5 + 1<<6}}) {
+
+	if len(hasCBCOnlyCipherSuites(&Service{Details: []int{enabled, tls.VersionTLS12, 0, 1 << TLSCipherSuitesCustom}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasTLSDisabled(&Service{Details: []int{1 << 5}}) {
+	if len(hasInsecureCipherSuites(&Service{Endpoints: []Endpoint{{Backends: []Backend{
+		{Components: Component{"backend/http/client": []int{1, tls.VersionTLS12, weak}}},
+	}}}})) == 0 {
 		t.Error("false negative")
 	}
 }
 
+func Test_hasMTLS(t *testing.T) {
+	mtls := 1<<ServiceHasTLS | 1<<ServiceTLSEnabled | 1<<ServiceTLSEnableMTLS
+	if len(hasMTLSWithSystemCaPool(&Service{Details: []int{mtls}})) == 0 {
+		t.Error("false negative")
+	}
+
+	if len(hasMTLSWithSystemCaPool(&Service{Details: []int{mtls | 1<<ServiceTLSDisableSystemCaPool}})) > 0 {
+		t.Error("false positive")
+	}
+
+	if len(hasMTLSWithoutCaCerts(&Service{Details: []int{mtls}})) == 0 {
+		t.Error("false negative")
+	}
+
+	if len(hasMTLSWithoutCaCerts(&Service{Details: []int{mtls | 1<<ServiceTLSCaCerts}})) > 0 {
+		t.Error("false positive")
+	}
+
+	if len(hasMTLSWithoutCaCerts(&Service{Details: []int{1<<ServiceHasTLS | 1<<ServiceTLSEnableMTLS}})) > 0 {
+		t.Error("false positive")
+	}
+}
+
 func Test_hasNoHTTPSecure(t *testing.T) {
-	if hasNoHTTPSecure(&Service{Components: Component{httpsecure.Namespace: []int{}}}) {
+	if len(hasNoHTTPSecure(&Service{Components: Component{httpsecure.Namespace: []int{}}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasNoHTTPSecure(&Service{Components: Component{}}) {
+	if len(hasNoHTTPSecure(&Service{Components: Component{}})) == 0 {
 		t.Error("false negative")
 	}
 }
 
 func Test_hasNoObfuscatedVersionHeader(t *testing.T) {
-	if hasNoObfuscatedVersionHeader(&Service{Components: Component{router.Namespace: []int{1 << 17}}}) {
+	if len(hasNoObfuscatedVersionHeader(&Service{Components: Component{router.Namespace: []int{1 << 17}}})) > 0 {
+		t.Error("false positive")
+	}
+
+	if len(hasNoObfuscatedVersionHeader(&Service{Components: Component{}})) == 0 {
+		t.Error("false negative")
+	}
+}
+
+func Test_hasRouterFlags(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		flag int
+		f    func(*Service) []Scope
+	}{
+		{name: "return_error_msg", flag: RouterErrorMsg, f: hasRouterErrorMsg},
+		{name: "disable_health", flag: RouterDisableHealth, f: hasRouterHealthOff},
+		{name: "disable_path_decoding", flag: RouterPathDecoding, f: hasRouterPathDecodeOff},
+	} {
+		scopes := tc.f(&Service{Components: Component{router.Namespace: []int{1 << tc.flag}}})
+		if len(scopes) != 1 {
+			t.Errorf("%s: false negative", tc.name)
+		} else if p := scopes[0].Pointer(); p != "/extra_config/github_com~1luraproject~1lura~1router~1gin/"+tc.name {
+			t.Errorf("%s: unexpected pointer %s", tc.name, p)
+		}
+
+		if len(tc.f(&Service{Components: Component{router.Namespace: []int{1 << RouterUseH2C}}})) > 0 {
+			t.Errorf("%s: false positive", tc.name)
+		}
+
+		if len(tc.f(&Service{Components: Component{}})) > 0 {
+			t.Errorf("%s: false positive", tc.name)
+		}
+	}
+}
+
+func Test_hasUntrustedClientIP(t *testing.T) {
+	if len(hasUntrustedClientIP(&Service{Components: Component{router.Namespace: []int{1 << RouterForwardedByClientIp}}})) == 0 {
+		t.Error("false negative")
+	}
+
+	if len(hasUntrustedClientIP(&Service{Components: Component{router.Namespace: []int{1<<RouterForwardedByClientIp | 1<<RouterTrustedProxies}}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasNoObfuscatedVersionHeader(&Service{Components: Component{}}) {
+	if len(hasUntrustedClientIP(&Service{Components: Component{router.Namespace: []int{1 << RouterTrustedProxies}}})) > 0 {
+		t.Error("false positive")
+	}
+}
+
+func Test_hasNoAccessLog(t *testing.T) {
+	if len(hasNoAccessLog(&Service{Components: Component{router.Namespace: []int{1 << RouterDisableAccessLog}}})) == 0 {
 		t.Error("false negative")
 	}
+
+	if len(hasNoAccessLog(&Service{Components: Component{router.Namespace: []int{1 << RouterDisableAccessLog}, gologging.Namespace: []int{}}})) > 0 {
+		t.Error("false positive")
+	}
+
+	if len(hasNoAccessLog(&Service{Components: Component{}})) > 0 {
+		t.Error("false positive")
+	}
 }
 
 func Test_hasNoCORS(t *testing.T) {
-	if hasNoCORS(&Service{Components: Component{cors.Namespace: []int{1 << 17}}}) {
+	if len(hasNoCORS(&Service{Components: Component{cors.Namespace: []int{1 << 17}}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasNoCORS(&Service{Components: Component{}}) {
+	if len(hasNoCORS(&Service{Components: Component{}})) == 0 {
 		t.Error("false negative")
 	}
 }
 
 func Test_hasBotdetectorDisabled(t *testing.T) {
-	if hasBotdetectorDisabled(&Service{Components: Component{botdetector.Namespace: []int{1 << 17}}}) {
+	if len(hasBotdetectorDisabled(&Service{Components: Component{botdetector.Namespace: []int{1 << 17}}})) > 0 {
+		t.Error("false positive")
+	}
+
+	if len(hasBotdetectorDisabled(&Service{Components: Component{}})) == 0 {
+		t.Error("false negative")
+	}
+}
+
+func Test_hasInertBotDetector(t *testing.T) {
+	if len(hasInertBotDetector(&Service{Components: Component{botdetector.Namespace: []int{0, 0, 0, 0, 0}}})) == 0 {
+		t.Error("false negative")
+	}
+
+	if len(hasInertBotDetector(&Service{Endpoints: []Endpoint{{Components: Component{botdetector.Namespace: []int{0, 0, 0, 0, 0}}}}})) == 0 {
+		t.Error("false negative")
+	}
+
+	if len(hasInertBotDetector(&Service{Components: Component{botdetector.Namespace: []int{0, 0, 0, 0, 1}}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasBotdetectorDisabled(&Service{Components: Component{}}) {
+	if len(hasInertBotDetector(&Service{Components: Component{botdetector.Namespace: []int{0, 2, 0, 0, 0}}})) > 0 {
+		t.Error("false positive")
+	}
+}
+
+func Test_hasUncachedBotDetector(t *testing.T) {
+	if len(hasUncachedBotDetector(&Service{Components: Component{botdetector.Namespace: []int{0, 0, 20, 0, 0}}})) == 0 {
 		t.Error("false negative")
 	}
+
+	if len(hasUncachedBotDetector(&Service{Components: Component{botdetector.Namespace: []int{0, 0, 20, 100, 0}}})) > 0 {
+		t.Error("false positive")
+	}
+
+	if len(hasUncachedBotDetector(&Service{Components: Component{botdetector.Namespace: []int{0, 0, 2, 0, 0}}})) > 0 {
+		t.Error("false positive")
+	}
+}
+
+func Test_hasInconsistentBotDetection(t *testing.T) {
+	endpoints := []Endpoint{
+		{Components: Component{botdetector.Namespace: []int{1, 0, 0, 0, 0}}},
+		{Components: Component{}},
+	}
+	scopes := hasInconsistentBotDetection(&Service{Endpoints: endpoints})
+	if len(scopes) != 1 || scopes[0].Pointer() != "/endpoints/1/extra_config/github_com~1devopsfaith~1krakend-botdetector" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+
+	if len(hasInconsistentBotDetection(&Service{Endpoints: endpoints, Components: Component{botdetector.Namespace: []int{1, 0, 0, 0, 0}}})) > 0 {
+		t.Error("false positive")
+	}
+
+	if len(hasInconsistentBotDetection(&Service{Endpoints: []Endpoint{{Components: Component{}}}})) > 0 {
+		t.Error("false positive")
+	}
 }
 
 func Test_hasNoRatelimit(t *testing.T) {
-	if hasNoRatelimit(&Service{Components: Component{ratelimit.Namespace: []int{1 << 17}}}) {
+	if len(hasNoRatelimit(&Service{Components: Component{ratelimit.Namespace: []int{1 << 17}}})) > 0 {
 		t.Error("false positive")
 	}
-	if hasNoRatelimit(&Service{Endpoints: []Endpoint{{Components: Component{ratelimit.Namespace: []int{1 << 17}}}}}) {
+	if len(hasNoRatelimit(&Service{Endpoints: []Endpoint{{Components: Component{ratelimit.Namespace: []int{1 << 17}}}}})) > 0 {
 		t.Error("false positive")
 	}
-	if hasNoRatelimit(&Service{Endpoints: []Endpoint{{Components: Component{ratelimitProxy.Namespace: []int{1 << 17}}}}}) {
+	if len(hasNoRatelimit(&Service{Endpoints: []Endpoint{{Components: Component{ratelimitProxy.Namespace: []int{1 << 17}}}}})) > 0 {
 		t.Error("false positive")
 	}
-	if hasNoRatelimit(&Service{Endpoints: []Endpoint{{Backends: []Backend{{Components: Component{ratelimitProxy.Namespace: []int{1 << 17}}}}}}}) {
+	if len(hasNoRatelimit(&Service{Endpoints: []Endpoint{{Backends: []Backend{{Components: Component{ratelimitProxy.Namespace: []int{1 << 17}}}}}}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasNoRatelimit(&Service{Components: Component{}}) {
-		t.Error("false negative")
+	if scopes := hasNoRatelimit(&Service{Components: Component{}}); len(scopes) != 1 || scopes[0].Pointer() != "/extra_config/qos~1ratelimit~1router" {
+		t.Errorf("unexpected scopes: %v", scopes)
 	}
 }
 
 func Test_hasNoCB(t *testing.T) {
-	if hasNoCB(&Service{Endpoints: []Endpoint{{Components: Component{cb.Namespace: []int{1 << 17}}}}}) {
+	if len(hasNoCB(&Service{Endpoints: []Endpoint{{Components: Component{cb.Namespace: []int{1 << 17}}}}})) > 0 {
 		t.Error("false positive")
 	}
-	if hasNoCB(&Service{Endpoints: []Endpoint{{Backends: []Backend{{Components: Component{cb.Namespace: []int{1 << 17}}}}}}}) {
+	if len(hasNoCB(&Service{Endpoints: []Endpoint{{Backends: []Backend{{Components: Component{cb.Namespace: []int{1 << 17}}}}}}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasNoCB(&Service{Components: Component{}}) {
+	if len(hasNoCB(&Service{Components: Component{}})) == 0 {
 		t.Error("false negative")
 	}
 }
 
 func Test_hasTimeoutBiggerThan(t *testing.T) {
-	if hasTimeoutBiggerThan(1000)(&Service{Endpoints: []Endpoint{{Details: []int{0, 0, 0, 100}}}}) {
+	if len(hasTimeoutBiggerThan(1000)(&Service{Endpoints: []Endpoint{{Details: []int{0, 0, 0, 100}}}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasTimeoutBiggerThan(1000)(&Service{Endpoints: []Endpoint{{Details: []int{0, 0, 0, 10000}}}}) {
+	if len(hasTimeoutBiggerThan(1000)(&Service{Endpoints: []Endpoint{{Details: []int{0, 0, 0, 10000}}}})) == 0 {
 		t.Error("false negative")
 	}
 }
 
 func Test_hasNoMetrics(t *testing.T) {
-	if hasNoMetrics(&Service{Components: Component{opencensus.Namespace: []int{1 << 17}}}) {
+	if len(hasNoMetrics(&Service{Components: Component{opencensus.Namespace: []int{1 << 17}}})) > 0 {
+		t.Error("false positive")
+	}
+	if len(hasNoMetrics(&Service{Components: Component{metrics.Namespace: []int{1 << 17}}})) > 0 {
+		t.Error("false positive")
+	}
+	if len(hasNoMetrics(&Service{Components: Component{"telemetry/newrelic": []int{1 << 17}}})) > 0 {
+		t.Error("false positive")
+	}
+	if len(hasNoMetrics(&Service{Components: Component{"telemetry/ganalytics": []int{1 << 17}}})) > 0 {
 		t.Error("false positive")
 	}
-	if hasNoMetrics(&Service{Components: Component{metrics.Namespace: []int{1 << 17}}}) {
+	if len(hasNoMetrics(&Service{Components: Component{"telemetry/instana": []int{1 << 17}}})) > 0 {
 		t.Error("false positive")
 	}
-	if hasNoMetrics(&Service{Components: Component{"telemetry/newrelic": []int{1 << 17}}}) {
+
+	if len(hasNoMetrics(&Service{Components: Component{"telemetry/opentelemetry": []int{30, 10, 1, 1, 0, 1, 0}}})) > 0 {
+		t.Error("false positive")
+	}
+	if len(hasNoMetrics(&Service{Components: Component{"telemetry/opentelemetry": []int{30, 10, 0, 1, 1, 2, 0}}})) > 0 {
+		t.Error("false positive")
+	}
+
+	if len(hasNoMetrics(&Service{Components: Component{}})) == 0 {
+		t.Error("false negative")
+	}
+	if len(hasNoMetrics(&Service{Components: Component{"telemetry/opentelemetry": []int{30, 10, 0, 1, 0, 1, 0}}})) == 0 {
+		t.Error("false negative")
+	}
+}
+
+func Test_hasOTELSettings(t *testing.T) {
+	if len(hasShortMetricReportingPeriod(&Service{Components: Component{"telemetry/opentelemetry": []int{1, -1, 1, 1, 0, 1, 0}}})) == 0 {
+		t.Error("false negative")
+	}
+	if len(hasShortMetricReportingPeriod(&Service{Components: Component{"telemetry/opentelemetry": []int{-1, -1, 1, 1, 0, 1, 0}}})) > 0 {
 		t.Error("false positive")
 	}
-	if hasNoMetrics(&Service{Components: Component{"telemetry/ganalytics": []int{1 << 17}}}) {
+	if len(hasShortMetricReportingPeriod(&Service{Components: Component{"telemetry/opentelemetry": []int{0, -1, 1, 1, 0, 1, 0}}})) > 0 {
 		t.Error("false positive")
 	}
-	if hasNoMetrics(&Service{Components: Component{"telemetry/instana": []int{1 << 17}}}) {
+
+	if len(hasFullTraceSampling(&Service{Components: Component{"telemetry/opentelemetry": []int{30, 100, 1, 1, 0, 1, 0}}})) == 0 {
+		t.Error("false negative")
+	}
+	if len(hasFullTraceSampling(&Service{Components: Component{"telemetry/opentelemetry": []int{30, 10, 1, 1, 0, 1, 0}}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasNoMetrics(&Service{Components: Component{}}) {
+	scopes := hasInsecureOTLPExporter(&Service{Components: Component{"telemetry/opentelemetry": []int{30, 10, 1, 1, 0, 3, 1 << 2}}})
+	if len(scopes) != 1 || scopes[0].Pointer() != "/extra_config/telemetry~1opentelemetry/exporters/otlp/2/host" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+
+	if len(hasAllOTELExportersDisabled(&Service{Components: Component{"telemetry/opentelemetry": []int{30, 10, 0, 0, 0, 2, 0}}})) == 0 {
 		t.Error("false negative")
 	}
+	if len(hasAllOTELExportersDisabled(&Service{Components: Component{"telemetry/opentelemetry": []int{30, 10, 0, 1, 0, 2, 0}}})) > 0 {
+		t.Error("false positive")
+	}
+	if len(hasAllOTELExportersDisabled(&Service{Components: Component{"telemetry/opentelemetry": []int{30, 10, 0, 0, 0, 0, 0}}})) > 0 {
+		t.Error("false positive")
+	}
 }
 
 func Test_hasSeveralTelemetryComponents(t *testing.T) {
-	if hasSeveralTelemetryComponents(&Service{Components: Component{opencensus.Namespace: []int{1 << 17}}}) {
+	if len(hasSeveralTelemetryComponents(&Service{Components: Component{opencensus.Namespace: []int{1 << 17}}})) > 0 {
 		t.Error("false positive")
 	}
-	if hasSeveralTelemetryComponents(&Service{Components: Component{metrics.Namespace: []int{1 << 17}}}) {
+	if len(hasSeveralTelemetryComponents(&Service{Components: Component{metrics.Namespace: []int{1 << 17}}})) > 0 {
 		t.Error("false positive")
 	}
-	if hasSeveralTelemetryComponents(&Service{Components: Component{"telemetry/newrelic": []int{1 << 17}}}) {
+	if len(hasSeveralTelemetryComponents(&Service{Components: Component{"telemetry/newrelic": []int{1 << 17}}})) > 0 {
 		t.Error("false positive")
 	}
-	if hasSeveralTelemetryComponents(&Service{Components: Component{"telemetry/ganalytics": []int{1 << 17}}}) {
+	if len(hasSeveralTelemetryComponents(&Service{Components: Component{"telemetry/ganalytics": []int{1 << 17}}})) > 0 {
 		t.Error("false positive")
 	}
-	if hasSeveralTelemetryComponents(&Service{Components: Component{"telemetry/instana": []int{1 << 17}}}) {
+	if len(hasSeveralTelemetryComponents(&Service{Components: Component{"telemetry/instana": []int{1 << 17}}})) > 0 {
 		t.Error("false positive")
 	}
-	if hasSeveralTelemetryComponents(&Service{Components: Component{}}) {
+	if len(hasSeveralTelemetryComponents(&Service{Components: Component{}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasSeveralTelemetryComponents(&Service{Components: Component{
+	if len(hasSeveralTelemetryComponents(&Service{Components: Component{
 		opencensus.Namespace: []int{1 << 17},
 		metrics.Namespace:    []int{1 << 17},
-	}}) {
+	}})) == 0 {
 		t.Error("false negative")
 	}
 }
 
 func Test_hasNoTracing(t *testing.T) {
-	if hasNoTracing(&Service{Components: Component{opencensus.Namespace: []int{1 << 17}}}) {
+	if len(hasNoTracing(&Service{Components: Component{opencensus.Namespace: []int{1 << 17}}})) > 0 {
 		t.Error("false positive")
 	}
-	if hasNoTracing(&Service{Components: Component{"telemetry/newrelic": []int{1 << 17}}}) {
+	if len(hasNoTracing(&Service{Components: Component{"telemetry/newrelic": []int{1 << 17}}})) > 0 {
 		t.Error("false positive")
 	}
-	if hasNoTracing(&Service{Components: Component{"telemetry/instana": []int{1 << 17}}}) {
+	if len(hasNoTracing(&Service{Components: Component{"telemetry/instana": []int{1 << 17}}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasNoTracing(&Service{Components: Component{}}) {
+	if len(hasNoTracing(&Service{Components: Component{}})) == 0 {
 		t.Error("false negative")
 	}
 }
 
 func Test_hasNoLogging(t *testing.T) {
-	if hasNoLogging(&Service{Components: Component{gologging.Namespace: []int{1 << 17}}}) {
+	if len(hasNoLogging(&Service{Components: Component{gologging.Namespace: []int{1 << 17}}})) > 0 {
 		t.Error("false positive")
 	}
-	if hasNoLogging(&Service{Components: Component{gelf.Namespace: []int{1 << 17}}}) {
+	if len(hasNoLogging(&Service{Components: Component{gelf.Namespace: []int{1 << 17}}})) > 0 {
 		t.Error("false positive")
 	}
-	if hasNoLogging(&Service{Components: Component{logstash.Namespace: []int{1 << 17}}}) {
+	if len(hasNoLogging(&Service{Components: Component{logstash.Namespace: []int{1 << 17}}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasNoLogging(&Service{Components: Component{}}) {
+	if len(hasNoLogging(&Service{Components: Component{}})) == 0 {
 		t.Error("false negative")
 	}
 }
 
 func Test_hasRestfulDisabled(t *testing.T) {
-	if hasRestfulDisabled(&Service{Details: []int{0}}) {
+	if len(hasRestfulDisabled(&Service{Details: []int{0}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasRestfulDisabled(&Service{Details: []int{1 << ServiceDisableStrictREST}}) {
+	if len(hasRestfulDisabled(&Service{Details: []int{1 << ServiceDisableStrictREST}})) == 0 {
 		t.Error("false negative")
 	}
 }
 
 func Test_hasDebugEnabled(t *testing.T) {
-	if hasDebugEnabled(&Service{Details: []int{0}}) {
+	if len(hasDebugEnabled(&Service{Details: []int{0}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasDebugEnabled(&Service{Details: []int{1 << ServiceDebug}}) {
+	if len(hasDebugEnabled(&Service{Details: []int{1 << ServiceDebug}})) == 0 {
 		t.Error("false negative")
 	}
 }
 
 func Test_hasEndpointWithoutBackends(t *testing.T) {
-	if hasEndpointWithoutBackends(&Service{Endpoints: []Endpoint{{Backends: []Backend{{}}}}}) {
+	if len(hasEndpointWithoutBackends(&Service{Endpoints: []Endpoint{{Backends: []Backend{{}}}}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasEndpointWithoutBackends(&Service{Endpoints: []Endpoint{{}}}) {
+	if len(hasEndpointWithoutBackends(&Service{Endpoints: []Endpoint{{}}})) == 0 {
 		t.Error("false negative")
 	}
 }
 
 func Test_hasASingleBackendPerEndpoint(t *testing.T) {
-	if hasASingleBackendPerEndpoint(&Service{Endpoints: []Endpoint{{Backends: []Backend{{}, {}}}}}) {
+	if len(hasASingleBackendPerEndpoint(&Service{Endpoints: []Endpoint{{Backends: []Backend{{}, {}}}}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasASingleBackendPerEndpoint(&Service{Endpoints: []Endpoint{{Backends: []Backend{{}}}}}) {
+	if len(hasASingleBackendPerEndpoint(&Service{Endpoints: []Endpoint{{Backends: []Backend{{}}}}})) == 0 {
 		t.Error("false negative")
 	}
 }
 
 func Test_hasAllEndpointsAsNoop(t *testing.T) {
-	if hasAllEndpointsAsNoop(&Service{Endpoints: []Endpoint{{Details: []int{2}}}}) {
+	if len(hasAllEndpointsAsNoop(&Service{Endpoints: []Endpoint{{Details: []int{2}}}})) > 0 {
 		t.Error("false positive")
 	}
 
-	if !hasAllEndpointsAsNoop(&Service{Endpoints: []Endpoint{{Details: []int{1}}}}) {
+	if len(hasAllEndpointsAsNoop(&Service{Endpoints: []Endpoint{{Details: []int{1}}}})) == 0 {
 		t.Error("false negative")
 	}
 }
+
+func Test_hasWebSocketRules(t *testing.T) {
+	ws := func(d ...int) *Service {
+		v := make([]int, 11)
+		copy(v, d)
+		return &Service{
+			Components: Component{},
+			Endpoints: []Endpoint{
+				{Components: Component{}},
+				{Components: Component{"websocket": v}},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		f        func(*Service) []Scope
+		positive *Service
+		negative *Service
+	}{
+		{
+			name:     "max_message_size",
+			f:        hasUnboundedWebSocketMessages,
+			positive: ws(0, 0, 0, 0, 2<<20, 1),
+			negative: ws(0, 0, 0, 0, 4096, 1),
+		},
+		{
+			name:     "return_error_details",
+			f:        hasWebSocketErrorDetails,
+			positive: ws(1<<2, 0, 0, 0, 0, 1),
+			negative: ws(1<<1, 0, 0, 0, 0, 1),
+		},
+		{
+			name:     "ping_period",
+			f:        hasWebSocketPingAfterPong,
+			positive: ws(0, 0, 0, 0, 0, 1, 0, 30000),
+			negative: ws(0, 0, 0, 0, 0, 1, 0, 30000, 20000),
+		},
+		{
+			name:     "buffers",
+			f:        hasLargeWebSocketBuffers,
+			positive: ws(0, 4096, 4096, 4096, 1<<20, 1),
+			negative: ws(0, 4096, 4096, 256, 4096, 1),
+		},
+		{
+			name:     "max_retries",
+			f:        hasUnlimitedWebSocketRetries,
+			positive: ws(0, 0, 0, 0, 0, 0),
+			negative: ws(0, 0, 0, 0, 0, 3),
+		},
+	} {
+		scopes := tc.f(tc.positive)
+		if len(scopes) != 1 {
+			t.Errorf("%s: false negative", tc.name)
+		} else if p := scopes[0].Pointer(); !strings.HasPrefix(p, "/endpoints/1/extra_config/websocket") {
+			t.Errorf("%s: unexpected pointer %s", tc.name, p)
+		}
+
+		if len(tc.f(tc.negative)) > 0 {
+			t.Errorf("%s: false positive", tc.name)
+		}
+	}
+
+	s := ws()
+	if len(hasUnauthenticatedWebSocket(s)) != 1 {
+		t.Error("false negative")
+	}
+	if len(hasUnlimitedWebSocket(s)) != 1 {
+		t.Error("false negative")
+	}
+
+	s.Endpoints[1].Components[jose.ValidatorNamespace] = []int{}
+	if len(hasUnauthenticatedWebSocket(s)) > 0 {
+		t.Error("false positive")
+	}
+
+	s.Components["qos/ratelimit/service"] = []int{}
+	if len(hasUnlimitedWebSocket(s)) > 0 {
+		t.Error("false positive")
+	}
+}
+
+func Test_aiEndpoints_unknownSettings(t *testing.T) {
+	s := &Service{
+		Endpoints: []Endpoint{
+			{
+				Backends: []Backend{{Components: parseComponents(config.ExtraConfig{
+					"ai/llm": map[string]interface{}{"other": map[string]interface{}{}},
+				})}},
+				Components: Component{},
+			},
+			{Components: parseComponents(config.ExtraConfig{"ai/mcp": map[string]interface{}{}})},
+		},
+	}
+	if idx := aiEndpoints(s); !reflect.DeepEqual(idx, []int{0, 1}) {
+		t.Errorf("unexpected ai endpoints: %v", idx)
+	}
+	if scopes := hasLongAITimeout(s); len(scopes) > 0 {
+		t.Errorf("false positive %v", scopes)
+	}
+}
+
+func Test_hasAIRules(t *testing.T) {
+	s := &Service{
+		Components: Component{"ai/mcp": []int{1, 30, 30}},
+		Endpoints: []Endpoint{
+			{Details: []int{2, 0, 0, 2000, 0, 0}, Components: Component{}},
+			{
+				Details:    []int{2, 0, 0, 300000, 0, 1},
+				Backends:   []Backend{{Components: Component{"ai/llm": []int{0, 0, 0, 0, 2}}}},
+				Components: Component{},
+			},
+			{Details: []int{2, 0, 0, 2000, 0, 1}, Components: Component{"ai/mcp": []int{}}},
+		},
+	}
+
+	if scopes := hasUnauthenticatedAIEndpoint(s); len(scopes) != 2 {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := hasUnlimitedAIEndpoint(s); len(scopes) != 2 {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	s.Components[ratelimit.Namespace] = []int{}
+	if scopes := hasUnlimitedAIEndpoint(s); len(scopes) > 0 {
+		t.Errorf("the service rate limit is ignored: %v", scopes)
+	}
+	delete(s.Components, ratelimit.Namespace)
+	if scopes := hasLongAITimeout(s); len(scopes) != 1 || scopes[0].Pointer() != "/endpoints/1/timeout" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := hasOutdatedLLMVersion(s); len(scopes) != 1 || scopes[0].Pointer() != "/endpoints/1/backend/0/extra_config/ai~1llm/openai" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if len(hasLargeMCPServer(s)) == 0 {
+		t.Error("false negative")
+	}
+
+	s.Components["ai/mcp"] = []int{2, 30, 15}
+	s.Endpoints[1].Details[3] = 60000
+	s.Endpoints[1].Backends[0].Components["ai/llm"] = []int{2, 0, 0, 1, 0}
+	s.Endpoints[1].Components[jose.ValidatorNamespace] = []int{}
+	s.Endpoints[1].Backends[0].Components["governance/quota"] = []int{}
+	s.Endpoints[2].Components["auth/api-keys"] = []int{}
+	s.Endpoints[2].Components[ratelimit.Namespace] = []int{}
+
+	for name, f := range map[string]func(*Service) []Scope{
+		"auth":     hasUnauthenticatedAIEndpoint,
+		"limits":   hasUnlimitedAIEndpoint,
+		"timeout":  hasLongAITimeout,
+		"versions": hasOutdatedLLMVersion,
+		"mcp":      hasLargeMCPServer,
+	} {
+		if scopes := f(s); len(scopes) > 0 {
+			t.Errorf("%s: false positive %v", name, scopes)
+		}
+	}
+}
+
+func Test_isAPIVersion(t *testing.T) {
+	for k, expected := range map[string]bool{
+		"v1":          true,
+		"v1beta":      true,
+		"v2alpha1":    true,
+		"v":           false,
+		"credentials": false,
+		"version":     false,
+	} {
+		if isAPIVersion(k) != expected {
+			t.Errorf("unexpected result for %s", k)
+		}
+	}
+}
+
+func Test_hasLuaRules(t *testing.T) {
+	s := &Service{
+		Components: Component{luarouter.Namespace: []int{1, 0, 20}},
+		Endpoints: []Endpoint{
+			{
+				Backends: []Backend{
+					{Components: Component{}},
+					{Components: Component{luaproxy.BackendNamespace: []int{14, 1, 0}}},
+				},
+				Components: Component{luaproxy.ProxyNamespace: []int{3, 0, 2048}},
+			},
+			{
+				Backends:   []Backend{{Components: Component{luaproxy.BackendNamespace: []int{2, 0, 100}}}},
+				Components: Component{},
+			},
+		},
+	}
+
+	if scopes := hasLuaOpenLibs(s); len(scopes) != 1 || scopes[0].Pointer() != "/endpoints/0/backend/1/extra_config/github.com~1devopsfaith~1krakend-lua~1proxy~1backend/allow_open_libs" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := hasLuaLive(s); len(scopes) != 1 {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := hasLargeInlineLua(s); len(scopes) != 1 || scopes[0].Pointer() != "/endpoints/0/extra_config/github.com~1devopsfaith~1krakend-lua~1proxy" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := hasLuaInAllLayers(s); len(scopes) != 1 || scopes[0].Pointer() != "/endpoints/0/extra_config" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+
+	delete(s.Components, luarouter.Namespace)
+	if len(hasLuaInAllLayers(s)) > 0 {
+		t.Error("false positive")
+	}
+	s.Endpoints[0].Components[luarouter.Namespace] = []int{1, 0, 20}
+	if len(hasLuaInAllLayers(s)) == 0 {
+		t.Error("false negative")
+	}
+}
+
+func Test_hasResponseSchemaRules(t *testing.T) {
+	s := &Service{
+		Endpoints: []Endpoint{
+			{
+				Backends:   []Backend{{Components: Component{responseSchemaNamespace: []int{20480, 1, 200, 0}}}},
+				Components: Component{plugin.Namespace: []int{2}},
+			},
+			{
+				Backends:   []Backend{{Components: Component{}}},
+				Components: Component{responseSchemaNamespace: []int{18, 1, 502, 1}},
+			},
+		},
+	}
+
+	if scopes := hasResponseSchemaErrorWithoutContentType(s); len(scopes) != 1 || scopes[0].Pointer() != "/endpoints/0/backend/0/extra_config/validation~1response-json-schema/error/content_type" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := hasResponseSchemaNonErrorStatus(s); len(scopes) != 1 || scopes[0].Pointer() != "/endpoints/0/backend/0/extra_config/validation~1response-json-schema/error/status" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := hasLargeResponseSchema(s); len(scopes) != 1 {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := hasDuplicatedResponseSchemaValidation(s); len(scopes) != 1 || scopes[0].Pointer() != "/endpoints/0/extra_config" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+
+	s.Endpoints[0].Components[plugin.Namespace] = []int{4}
+	if len(hasDuplicatedResponseSchemaValidation(s)) > 0 {
+		t.Error("false positive")
+	}
+	s.Endpoints[1].Backends[0].Components[plugin.Namespace] = []int{2}
+	if len(hasDuplicatedResponseSchemaValidation(s)) == 0 {
+		t.Error("false negative")
+	}
+}
+
+func Test_hasResponseBodyModifierRules(t *testing.T) {
+	s := &Service{
+		Endpoints: []Endpoint{
+			{
+				Details: []int{1, 0, 0, 2000, 0, 0},
+				Backends: []Backend{
+					{Components: Component{responseBodyModifierNamespace: []int{4, 4, 0, 0, 0, 0}}},
+					{Components: Component{responseBodyModifierNamespace: []int{4, 2, 2, 0, 0, 0}}},
+					{Components: Component{}},
+				},
+				Components: Component{responseBodyModifierNamespace: []int{3, 0, 1, 1, 1, 0}},
+			},
+			{
+				Details:    []int{2, 0, 0, 2000, 0, 0},
+				Backends:   []Backend{{Components: Component{}}},
+				Components: Component{responseBodyModifierNamespace: []int{6, 6, 0, 0, 0, 0}},
+			},
+		},
+	}
+
+	if scopes := hasManyRegexpModifiers(s); len(scopes) != 4 {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := hasModifiersOnNoopEndpoint(s); len(scopes) != 3 || scopes[0].Pointer() != "/endpoints/0/extra_config/modifier~1response-body" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := hasLongModifierChainOnFanOut(s); len(scopes) != 3 {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+
+	s.Endpoints[0].Details[0] = 2
+	s.Endpoints[0].Backends[0].Components[responseBodyModifierNamespace] = []int{1, 1, 0, 0, 0, 0}
+	s.Endpoints[1].Components[responseBodyModifierNamespace] = []int{5, 5, 0, 0, 0, 0}
+	for name, f := range map[string]func(*Service) []Scope{
+		"regexp": hasManyRegexpModifiers,
+		"noop":   hasModifiersOnNoopEndpoint,
+		"chain":  hasLongModifierChainOnFanOut,
+	} {
+		if scopes := f(s); len(scopes) > 0 {
+			t.Errorf("%s: false positive %v", name, scopes)
+		}
+	}
+}
+
+func Test_hasBackendHostRules(t *testing.T) {
+	s := &Service{
+		Details: []int{1<<ServiceHasTLS | 1<<ServiceTLSEnabled},
+		Endpoints: []Endpoint{
+			{
+				Backends: []Backend{
+					{Details: []int{0, 1<<BackendHostHTTP | 1<<BackendHostPlainRemote | 1<<BackendHostHTTPS | 1<<BackendHostName, BackendSDStatic, 2}},
+					{Details: []int{0, 1<<BackendHostHTTP | 1<<BackendHostLoopback, BackendSDStatic, 1}},
+				},
+			},
+		},
+		Agents: []Agent{
+			{Backends: []Backend{{Details: []int{0, 1<<BackendHostHTTPS | 1<<BackendHostIP | 1<<BackendHostPublic, BackendSDStatic, 1}}}},
+		},
+	}
+
+	if scopes := hasPlaintextBackend(s); len(scopes) != 1 || scopes[0].Pointer() != "/endpoints/0/backend/0/host" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	hostname := &Service{
+		Details: s.Details,
+		Endpoints: []Endpoint{{Backends: []Backend{
+			{Details: []int{0, parseHosts([]string{"http://orders.internal:8080"}), BackendSDStatic, 1}},
+			{Details: []int{0, parseHosts([]string{"http://10.0.0.10:8080"}), BackendSDStatic, 1}},
+		}}},
+	}
+	if scopes := hasPlaintextBackend(hostname); len(scopes) != 1 || scopes[0].Pointer() != "/endpoints/0/backend/0/host" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	s.Details[ServiceDetailFlags] = 0
+	if scopes := hasPlaintextBackend(s); len(scopes) > 0 {
+		t.Errorf("plaintext backends reported without TLS in the gateway: %v", scopes)
+	}
+	if scopes := hasMixedBackendSchemes(s); len(scopes) != 1 || scopes[0].Pointer() != "/endpoints/0/backend/0/host" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := hasLoopbackBackend(s); len(scopes) != 1 || scopes[0].Pointer() != "/endpoints/0/backend/1/host" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := hasSingleHostBackend(s); len(scopes) != 1 || scopes[0].Pointer() != "/async_agent/0/backend/0/host" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+
+	s.Agents[0].Backends[0].Details[BackendDetailSD] = BackendSDDNS
+	s.Endpoints[0].Backends = []Backend{{Details: []int{0}}}
+	for name, f := range map[string]func(*Service) []Scope{
+		"plaintext": hasPlaintextBackend,
+		"mixed":     hasMixedBackendSchemes,
+		"loopback":  hasLoopbackBackend,
+		"single":    hasSingleHostBackend,
+	} {
+		if scopes := f(s); len(scopes) > 0 {
+			t.Errorf("%s: false positive %v", name, scopes)
+		}
+	}
+}
+
+func Test_hasRouteRules(t *testing.T) {
+	names := routeSegmentNames{}
+	endpoint := func(method int, path string) Endpoint {
+		details := make([]int, EndpointDetailRoute)
+		details[EndpointDetailMethod] = 1 << method
+		if path == "/__catchall" {
+			details[EndpointDetailWildcards] = 1 << BitEndpointCatchAll
+		}
+		return Endpoint{Details: append(details, parseRoute(path, names)...)}
+	}
+	s := &Service{
+		Endpoints: []Endpoint{
+			endpoint(MethodGET, "/users/{id}"),
+			endpoint(MethodPOST, "/users/{id}"),
+			endpoint(MethodGET, "/users/{name}/posts"),
+			endpoint(MethodGET, "/users/:id"),
+			endpoint(MethodGET, "/files/*"),
+			endpoint(MethodGET, "/files/{file}/metadata"),
+			endpoint(MethodPOST, "/files/upload"),
+			endpoint(MethodGET, "/__catchall"),
+			endpoint(MethodGET, "/__catchall/users"),
+			endpoint(MethodGET, "/__catchall"),
+		},
+	}
+
+	if scopes := hasDuplicatedRoutes(s); len(scopes) != 2 || scopes[0].Pointer() != "/endpoints/3/endpoint" || scopes[1].Pointer() != "/endpoints/9/endpoint" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := hasRouteParamConflicts(s); len(scopes) != 2 || scopes[0].Pointer() != "/endpoints/2/endpoint" || scopes[1].Pointer() != "/endpoints/3/endpoint" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := hasRoutesShadowedByWildcard(s); len(scopes) != 1 || scopes[0].Pointer() != "/endpoints/5/endpoint" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+
+	s.Endpoints = []Endpoint{
+		endpoint(MethodGET, "/users/{id}"),
+		endpoint(MethodGET, "/users/{id}/posts"),
+		endpoint(MethodGET, "/users/me"),
+		endpoint(MethodPOST, "/files/*"),
+		endpoint(MethodGET, "/files/{file}"),
+		endpoint(MethodGET, "/__catchall"),
+		endpoint(MethodPOST, "/__catchall/users"),
+		endpoint(MethodGET, "/__catchallusers"),
+		{Details: []int{2, 0, 0, 2000, 0, 0}},
+	}
+	for name, f := range map[string]func(*Service) []Scope{
+		"duplicated": hasDuplicatedRoutes,
+		"params":     hasRouteParamConflicts,
+		"wildcard":   hasRoutesShadowedByWildcard,
+	} {
+		if scopes := f(s); len(scopes) > 0 {
+			t.Errorf("%s: false positive %v", name, scopes)
+		}
+	}
+}
+
+func Test_hasServerTimeoutRules(t *testing.T) {
+	s := &Service{
+		Details: make([]int, ServiceDetailMaxHeaderBytes+1),
+		Endpoints: []Endpoint{
+			{Details: []int{2, 0, 0, 2000, 0, 0}},
+			{Details: []int{2, 0, 0, 10000, 0, 0}},
+			{Details: []int{2}},
+		},
+	}
+	s.Details[ServiceDetailWriteTimeout] = 5000
+	s.Details[ServiceDetailMaxHeaderBytes] = 2 << 20
+
+	for name, f := range map[string]func(*Service) []Scope{
+		"read_header_timeout": hasNoReadHeaderTimeout,
+		"write_timeout":       hasShortWriteTimeout,
+		"idle_timeout":        hasUnboundedIdleConnections,
+		"max_header_bytes":    hasLargeMaxHeaderBytes,
+	} {
+		if scopes := f(s); len(scopes) != 1 || scopes[0].Pointer() != "/"+name {
+			t.Errorf("%s: unexpected scopes %v", name, scopes)
+		}
+	}
+
+	s.Details[ServiceDetailReadHeaderTimeout] = 1000
+	if scopes := hasUnboundedIdleConnections(s); len(scopes) != 1 {
+		t.Errorf("the read header timeout does not bound the idle connections: %v", scopes)
+	}
+
+	s.Details[ServiceDetailReadHeaderTimeout] = 0
+	s.Details[ServiceDetailReadTimeout] = 10000
+	if scopes := hasNoReadHeaderTimeout(s); len(scopes) > 0 {
+		t.Errorf("the read timeout does not bound the request headers: %v", scopes)
+	}
+	s.Details[ServiceDetailWriteTimeout] = 15000
+	s.Details[ServiceDetailMaxHeaderBytes] = 0
+	for name, f := range map[string]func(*Service) []Scope{
+		"read_header_timeout": hasNoReadHeaderTimeout,
+		"write_timeout":       hasShortWriteTimeout,
+		"idle_timeout":        hasUnboundedIdleConnections,
+		"max_header_bytes":    hasLargeMaxHeaderBytes,
+	} {
+		if scopes := f(s); len(scopes) > 0 {
+			t.Errorf("%s: false positive %v", name, scopes)
+		}
+	}
+}
+
+func Test_hasCacheRules(t *testing.T) {
+	endpoint := func(encoding, method, cacheTTL, credentialHeaders int, components ...Component) Endpoint {
+		details := make([]int, EndpointDetailRoute)
+		details[EndpointDetailEncoding] = 1 << encoding
+		details[EndpointDetailMethod] = 1 << method
+		details[EndpointDetailCacheTTL] = cacheTTL
+		details[EndpointDetailCredentialHeaders] = credentialHeaders
+		var backends []Backend
+		for _, c := range components {
+			backends = append(backends, Backend{Components: c})
+		}
+		return Endpoint{Details: details, Backends: backends, Components: Component{}}
+	}
+	shared := Component{httpcache.Namespace: []int{1 << HTTPCacheShared}}
+	s := &Service{
+		Endpoints: []Endpoint{
+			endpoint(EncodingJSON, MethodGET, 0, 1<<CredentialHeaderCookie, Component{}, shared),
+			endpoint(EncodingJSON, MethodPOST, 300000, 0),
+			endpoint(EncodingNOOP, MethodGET, 60000, 0, Component{httpcache.Namespace: []int{0}}),
+			endpoint(EncodingJSON, MethodGET, 0, 0, shared),
+		},
+	}
+	s.Endpoints[3].Components[jose.ValidatorNamespace] = []int{}
+
+	if scopes := hasSharedCacheOnAuthenticatedEndpoint(s); len(scopes) != 2 || scopes[0].Pointer() != "/endpoints/0/backend/1/extra_config/github.com~1devopsfaith~1krakend-httpcache/shared" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := hasLongCacheTTLOnUnsafeMethod(s); len(scopes) != 1 || scopes[0].Pointer() != "/endpoints/1/cache_ttl" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := hasCacheOnNoopEndpoint(s); len(scopes) != 2 {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+
+	s.Endpoints = []Endpoint{
+		endpoint(EncodingJSON, MethodGET, 300000, 0, shared),
+		endpoint(EncodingJSON, MethodPOST, 30000, 1<<CredentialHeaderAuthorization, Component{httpcache.Namespace: []int{0}}),
+		endpoint(EncodingNOOP, MethodGET, 0, 0, Component{}),
+		{},
+	}
+	for name, f := range map[string]func(*Service) []Scope{
+		"shared": hasSharedCacheOnAuthenticatedEndpoint,
+		"unsafe": hasLongCacheTTLOnUnsafeMethod,
+		"noop":   hasCacheOnNoopEndpoint,
+	} {
+		if scopes := f(s); len(scopes) > 0 {
+			t.Errorf("%s: false positive %v", name, scopes)
+		}
+	}
+}
+
+func Test_hasRatelimitDepthRules(t *testing.T) {
+	endpoint := func(method int, components Component) Endpoint {
+		details := make([]int, EndpointDetailRoute)
+		details[EndpointDetailMethod] = 1 << method
+		return Endpoint{Details: details, Components: components}
+	}
+	s := &Service{
+		Components: Component{
+			ratelimitServiceNamespace: []int{1<<RatelimitMaxRate | 1<<RatelimitClientMaxRate, 3600, 0, 7200, 0},
+		},
+		Endpoints: []Endpoint{
+			endpoint(MethodGET, Component{}),
+			endpoint(MethodPOST, Component{}),
+			endpoint(MethodDELETE, Component{ratelimit.Namespace: []int{1 << RatelimitClientMaxRate, 0, 0, 3600, 0}}),
+			endpoint(MethodPUT, Component{ratelimit.Namespace: []int{1<<RatelimitClientMaxRate | 1<<RatelimitStrategyHeader, 0, 0, 3600, 0}}),
+		},
+	}
+
+	if scopes := hasUnthrottledWriteEndpoint(s); len(scopes) != 2 || scopes[0].Pointer() != "/endpoints/1/extra_config/qos~1ratelimit~1router" || scopes[1].Pointer() != "/endpoints/2/extra_config/qos~1ratelimit~1router" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := hasClientRatelimitWithoutStrategy(s); len(scopes) != 2 || scopes[0].Pointer() != "/extra_config/qos~1ratelimit~1service/strategy" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := hasClientRatelimitWithoutKey(s); len(scopes) != 1 || scopes[0].Pointer() != "/endpoints/3/extra_config/qos~1ratelimit~1router/key" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := hasClientRateAboveMaxRate(s); len(scopes) != 1 || scopes[0].Pointer() != "/extra_config/qos~1ratelimit~1service/client_max_rate" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+
+	s.Components[ratelimitServiceNamespace] = []int{1<<RatelimitMaxRate | 1<<RatelimitClientMaxRate | 1<<RatelimitStrategyIP, 7200, 0, 3600, 0}
+	s.Endpoints[1].Components[ratelimit.Namespace] = []int{1 << RatelimitMaxRate, 3600, 0, 0, 0}
+	s.Endpoints[2].Components[ratelimit.Namespace] = []int{1<<RatelimitClientMaxRate | 1<<RatelimitStrategyParam | 1<<RatelimitKey, 0, 0, 3600, 0}
+	s.Endpoints[3].Components[ratelimit.Namespace] = []int{1<<RatelimitClientMaxRate | 1<<RatelimitStrategyHeader | 1<<RatelimitKey, 0, 0, 3600, 0}
+	for name, f := range map[string]func(*Service) []Scope{
+		"write":    hasUnthrottledWriteEndpoint,
+		"strategy": hasClientRatelimitWithoutStrategy,
+		"key":      hasClientRatelimitWithoutKey,
+		"rate":     hasClientRateAboveMaxRate,
+	} {
+		if scopes := f(s); len(scopes) > 0 {
+			t.Errorf("%s: false positive %v", name, scopes)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package audit
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Score summarizes the findings of the audit as a number between 0 and 100, where 100 means
+// that none of the evaluated rules has a finding, and its letter grade (A to F)
+type Score struct {
+	Overall  float64        `json:"overall"`
+	Grade    string         `json:"grade"`
+	Sections []SectionScore `json:"sections"`
+}
+
+// SectionScore is the score of the rules of a section of the rule set
+type SectionScore struct {
+	Section string  `json:"section"`
+	Name    string  `json:"name"`
+	Score   float64 `json:"score"`
+	Grade   string  `json:"grade"`
+}
+
+// ScoreWeights defines how much each finding penalizes the score. Every evaluated rule adds
+// the weight of its severity to the maximum penalty of its section, and every finding adds
+// that weight, scaled by how widely it applies, to the penalty of the section. Spread is the
+// share of the weight that depends on the ratio of endpoints affected by the finding: with
+// a spread of 0 a finding in one endpoint weighs as much as a finding in all of them, while
+// with a spread of 1 it only weighs its ratio of endpoints. Findings outside the endpoints
+// always apply their full weight
+type ScoreWeights struct {
+	Severities map[Severity]float64 `json:"severities"`
+	Spread     float64              `json:"spread"`
+}
+
+// DefaultScoreWeights returns the weights used when none are configured
+func DefaultScoreWeights() ScoreWeights {
+	return ScoreWeights{
+		Severities: map[Severity]float64{
+			SeverityCritical: 10,
+			SeverityHigh:     5,
+			SeverityMedium:   2,
+			SeverityLow:      1,
+		},
+		Spread: 0.5,
+	}
+}
+
+// WithScoreWeights replaces the default weights used to compute the score of the audit
+func WithScoreWeights(w ScoreWeights) Option {
+	return func(o *options) {
+		o.weights = w
+	}
+}
+
+// Validate checks that all the weights are in range and that every severity has a weight,
+// as a missing one would hide the findings of its severity from the score
+func (w ScoreWeights) Validate() error {
+	for _, s := range Severities() {
+		if _, ok := w.Severities[s]; !ok {
+			return fmt.Errorf("missing weight for %s", s)
+		}
+	}
+	for s, v := range w.Severities {
+		if !s.IsValid() {
+			return fmt.Errorf("%w: %q", ErrInvalidSeverity, string(s))
+		}
+		if v < 0 {
+			return fmt.Errorf("invalid weight for %s: %f", s, v)
+		}
+	}
+	if w.Spread < 0 || w.Spread > 1 {
+		return fmt.Errorf("invalid spread: %f", w.Spread)
+	}
+	return nil
+}
+
+// Grade returns the letter grade of a score
+func Grade(score float64) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	}
+	return "F"
+}
+
+type scorer struct {
+	weights   ScoreWeights
+	endpoints int
+	max       map[string]float64
+	penalty   map[string]float64
+}
+
+func newScorer(w ScoreWeights, endpoints int) *scorer {
+	return &scorer{
+		weights:   w,
+		endpoints: endpoints,
+		max:       map[string]float64{},
+		penalty:   map[string]float64{},
+	}
+}
+
+func (s *scorer) evaluated(id string, severity Severity) {
+	s.max[ruleSection(id)] += s.weights.Severities[severity]
+}
+
+func (s *scorer) finding(r Recommendation) {
+	s.penalty[ruleSection(r.Rule)] += s.weights.Severities[r.Severity] * s.reach(r.Locations)
+}
+
+// reach returns the share of the weight applied to a finding with the received locations
+func (s *scorer) reach(ls []Location) float64 {
+	if s.endpoints == 0 {
+		return 1
+	}
+	endpoints := map[string]struct{}{}
+	for _, l := range ls {
+		scope := parsePointer(l.Pointer)
+		if len(scope) < 2 || scope[0] != "endpoints" {
+			return 1
+		}
+		endpoints[scope[1]] = struct{}{}
+	}
+	ratio := math.Min(1, float64(len(endpoints))/float64(s.endpoints))
+	return 1 - s.weights.Spread + s.weights.Spread*ratio
+}
+
+func (s *scorer) score() Score {
+	sections := make([]string, 0, len(s.max))
+	for section := range s.max {
+		sections = append(sections, section)
+	}
+	sort.Slice(sections, func(i, j int) bool {
+		a, errA := strconv.Atoi(sections[i])
+		b, errB := strconv.Atoi(sections[j])
+		if errA == nil && errB == nil {
+			return a < b
+		}
+		if errA == nil || errB == nil {
+			return errA == nil
+		}
+		return sections[i] < sections[j]
+	})
+
+	res := Score{Sections: make([]SectionScore, 0, len(sections))}
+	var total, penalty float64
+	for _, section := range sections {
+		total += s.max[section]
+		penalty += s.penalty[section]
+		v := scoreOf(s.penalty[section], s.max[section])
+		res.Sections = append(res.Sections, SectionScore{
+			Section: section,
+			Name:    SectionName(section),
+			Score:   v,
+			Grade:   Grade(v),
+		})
+	}
+	res.Overall = scoreOf(penalty, total)
+	res.Grade = Grade(res.Overall)
+	return res
+}
+
+func scoreOf(penalty, total float64) float64 {
+	if total <= 0 {
+		return 100
+	}
+	v := 100 * (1 - penalty/total)
+	return math.Round(math.Max(0, v)*100) / 100
+}
@@ -0,0 +1,105 @@
+package audit
+
+import (
+	"testing"
+)
+
+func TestGrade(t *testing.T) {
+	for score, grade := range map[float64]string{
+		100:   "A",
+		90:    "A",
+		89.99: "B",
+		80:    "B",
+		75:    "C",
+		60:    "D",
+		59.99: "F",
+		0:     "F",
+	} {
+		if g := Grade(score); g != grade {
+			t.Errorf("unexpected grade for %f. have: %s, want: %s", score, g, grade)
+		}
+	}
+}
+
+func TestScorer(t *testing.T) {
+	s := newScorer(DefaultScoreWeights(), 4)
+	s.evaluated("1.1.1", SeverityHigh)
+	s.evaluated("1.1.2", SeverityLow)
+	s.evaluated("2.1.1", SeverityCritical)
+	s.evaluated("10.1.1", SeverityLow)
+	s.finding(Recommendation{
+		Rule:     "1.1.1",
+		Severity: SeverityHigh,
+		Locations: []Location{
+			{Pointer: "/endpoints/2/timeout"},
+			{Pointer: "/endpoints/2/backend/0/extra_config/qos~1http-cache"},
+		},
+	})
+	s.finding(Recommendation{Rule: "10.1.1", Severity: SeverityLow, Locations: []Location{{Pointer: ""}}})
+
+	score := s.score()
+	if score.Overall != 75.74 || score.Grade != "C" {
+		t.Errorf("unexpected overall score: %f (%s)", score.Overall, score.Grade)
+	}
+
+	expected := []SectionScore{
+		{Section: "1", Name: "Security", Score: 47.92, Grade: "F"},
+		{Section: "2", Name: "Service", Score: 100, Grade: "A"},
+		{Section: "10", Score: 0, Grade: "F"},
+	}
+	if len(score.Sections) != len(expected) {
+		t.Errorf("unexpected number of sections: %+v", score.Sections)
+		return
+	}
+	for i, e := range expected {
+		if score.Sections[i] != e {
+			t.Errorf("unexpected section score %d. have: %+v, want: %+v", i, score.Sections[i], e)
+		}
+	}
+}
+
+func TestRun_score(t *testing.T) {
+	cfg := loadExample1(t)
+	result, err := Run(cfg)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(result.Score.Sections) != 8 {
+		t.Errorf("unexpected number of sections: %+v", result.Score.Sections)
+	}
+	if result.Score.Overall <= 0 || result.Score.Overall >= 100 {
+		t.Errorf("unexpected overall score: %f", result.Score.Overall)
+	}
+
+	// ignoring the spread, every finding weighs the same
+	flat, err := Run(cfg, WithScoreWeights(ScoreWeights{Severities: DefaultScoreWeights().Severities}))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if flat.Score.Overall >= result.Score.Overall {
+		t.Errorf("the spread of the findings has been ignored: %f >= %f", flat.Score.Overall, result.Score.Overall)
+	}
+
+	v, err := result.Check(Policy{MinScore: 99})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if v.Pass || len(v.Violations) != 1 || v.Violations[0].Condition != ConditionMinScore {
+		t.Errorf("unexpected verdict: %+v", v)
+	}
+
+	if _, err := Run(cfg, WithScoreWeights(ScoreWeights{Severities: DefaultScoreWeights().Severities, Spread: 2})); err == nil {
+		t.Error("expecting an error with an invalid spread")
+	}
+	if _, err := Run(cfg, WithScoreWeights(ScoreWeights{Severities: map[Severity]float64{SeverityHigh: 5}})); err == nil {
+		t.Error("expecting an error with missing weights")
+	}
+	weights := DefaultScoreWeights()
+	weights.Severities[SeverityLow] = -1
+	if _, err := Run(cfg, WithScoreWeights(weights)); err == nil {
+		t.Error("expecting an error with a negative weight")
+	}
+}
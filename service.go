@@ -1,7 +1,9 @@
 package audit
 
 // Service represents a KrakenD configuration as a tree of bitsets representing
-// which components and flags are enabled at the KrakenD configuration
+// which components and flags are enabled at the KrakenD configuration. Agents,
+// endpoints and backends keep the order of the source configuration, so their
+// indices are the ones used by the JSON pointers of the rule scopes
 type Service struct {
 	Details    []int      `json:"d"`
 	Agents     []Agent    `json:"a"`
@@ -114,6 +116,35 @@ const (
 	ServiceEcho
 	ServiceUseH2C
 	ServiceTLSPrivPubKey
+	ServiceClientTLSAllowInsecureConnections
+)
+
+// Positions of the service details. The TLS positions are zero when the related
+// section is not declared, and the versions are the ones applied by the gateway.
+// The server timeouts are in milliseconds
+const (
+	ServiceDetailFlags = iota
+	ServiceDetailTLSMinVersion
+	ServiceDetailTLSMaxVersion
+	ServiceDetailTLSCipherSuites
+	ServiceDetailTLSCurves
+	ServiceDetailTLSKeys
+	ServiceDetailClientTLSMinVersion
+	ServiceDetailClientTLSMaxVersion
+	ServiceDetailClientTLSCipherSuites
+	ServiceDetailClientTLSCurves
+	ServiceDetailClientTLSCerts
+	ServiceDetailReadTimeout
+	ServiceDetailWriteTimeout
+	ServiceDetailIdleTimeout
+	ServiceDetailReadHeaderTimeout
+	ServiceDetailMaxHeaderBytes
+)
+
+const (
+	TLSCipherSuitesCustom = iota
+	TLSCipherSuitesInsecure
+	TLSCipherSuitesCBCOnly
 )
 
 const (
@@ -137,6 +168,33 @@ const (
 	BackendQuery
 )
 
+// Positions of the backend details
+const (
+	BackendDetailFlags = iota
+	BackendDetailHosts
+	BackendDetailSD
+	BackendDetailHostCount
+)
+
+// Bits of the hosts summary of the backends. The ranges are only known for IP literals
+const (
+	BackendHostHTTP = iota
+	BackendHostHTTPS
+	BackendHostOtherScheme
+	BackendHostPlainRemote
+	BackendHostName
+	BackendHostIP
+	BackendHostLoopback
+	BackendHostPrivate
+	BackendHostPublic
+)
+
+const (
+	BackendSDStatic = iota
+	BackendSDDNS
+	BackendSDOther
+)
+
 const (
 	RouterErrorBody = iota
 	RouterDisableHealth
@@ -159,6 +217,31 @@ const (
 	RouterUseH2C
 )
 
+// Bits of the flags of the router and service rate limits. The rates and capacities
+// follow the flags, with the rates in requests per hour
+const (
+	RatelimitMaxRate = iota
+	RatelimitClientMaxRate
+	RatelimitStrategyIP
+	RatelimitStrategyHeader
+	RatelimitStrategyParam
+	RatelimitStrategyUnknown
+	RatelimitKey
+)
+
+const (
+	HTTPCacheShared = iota
+	HTTPCacheMaxItems
+	HTTPCacheMaxSize
+)
+
+const (
+	LuaPre = iota
+	LuaPost
+	LuaAllowOpenLibs
+	LuaLive
+)
+
 const (
 	BackendComponentHTTPClient = iota
 	BackendComponentHTTPClientAllowInsecureConnections
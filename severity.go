@@ -0,0 +1,84 @@
+package audit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity is the level of risk of a recommendation. Severities are ordered from
+// SeverityLow, the lowest, to SeverityCritical, the highest
+type Severity string
+
+const (
+	SeverityCritical Severity = "CRITICAL"
+	SeverityHigh     Severity = "HIGH"
+	SeverityMedium   Severity = "MEDIUM"
+	SeverityLow      Severity = "LOW"
+)
+
+var severityLevels = map[Severity]int{
+	SeverityLow:      1,
+	SeverityMedium:   2,
+	SeverityHigh:     3,
+	SeverityCritical: 4,
+}
+
+// Severities returns the supported severities, from the highest to the lowest
+func Severities() []Severity {
+	return []Severity{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow}
+}
+
+// ParseSeverity returns the Severity represented by the received string, ignoring its case
+func ParseSeverity(s string) (Severity, error) {
+	res := Severity(strings.ToUpper(strings.TrimSpace(s)))
+	if !res.IsValid() {
+		return "", fmt.Errorf("%w: %q", ErrInvalidSeverity, s)
+	}
+	return res, nil
+}
+
+// IsValid reports if the severity is one of the supported ones
+func (s Severity) IsValid() bool {
+	_, ok := severityLevels[s]
+	return ok
+}
+
+// Compare returns -1 if the severity is lower than the received one, 0 if they are
+// equal and +1 if it is higher. Invalid severities are lower than any valid one
+func (s Severity) Compare(o Severity) int {
+	a, b := severityLevels[s], severityLevels[o]
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// AtLeast reports if the severity is equal to or higher than the received one
+func (s Severity) AtLeast(o Severity) bool {
+	return s.Compare(o) >= 0
+}
+
+func (s Severity) String() string {
+	return string(s)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (s Severity) MarshalText() ([]byte, error) {
+	if !s.IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSeverity, string(s))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface
+func (s *Severity) UnmarshalText(b []byte) error {
+	v, err := ParseSeverity(string(b))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
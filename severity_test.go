@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseSeverity(t *testing.T) {
+	for in, expected := range map[string]Severity{
+		"CRITICAL": SeverityCritical,
+		"high":     SeverityHigh,
+		" Medium ": SeverityMedium,
+		"LOW":      SeverityLow,
+	} {
+		s, err := ParseSeverity(in)
+		if err != nil {
+			t.Errorf("parsing %q: %v", in, err)
+			continue
+		}
+		if s != expected {
+			t.Errorf("unexpected severity. have: %s, want: %s", s, expected)
+		}
+	}
+
+	for _, in := range []string{"", "HIHG", "INFO"} {
+		if _, err := ParseSeverity(in); !errors.Is(err, ErrInvalidSeverity) {
+			t.Errorf("unexpected error parsing %q: %v", in, err)
+		}
+	}
+}
+
+func TestSeverity_Compare(t *testing.T) {
+	ss := Severities()
+	for i := range ss {
+		for j := range ss {
+			expected := 0
+			if i < j {
+				expected = 1
+			} else if i > j {
+				expected = -1
+			}
+			if c := ss[i].Compare(ss[j]); c != expected {
+				t.Errorf("unexpected comparison of %s and %s. have: %d, want: %d", ss[i], ss[j], c, expected)
+			}
+		}
+	}
+
+	if !Severity(SeverityHigh).AtLeast(SeverityMedium) {
+		t.Error("HIGH should be at least MEDIUM")
+	}
+	if Severity("UNKNOWN").AtLeast(SeverityLow) {
+		t.Error("invalid severities should be lower than LOW")
+	}
+}
+
+func TestSeverity_json(t *testing.T) {
+	var r Recommendation
+	if err := json.Unmarshal([]byte(`{"rule":"2.2.2","severity":"low","original_severity":"HIGH"}`), &r); err != nil {
+		t.Error(err)
+		return
+	}
+	if r.Severity != SeverityLow || r.OriginalSeverity != SeverityHigh {
+		t.Errorf("unexpected severities: %+v", r)
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if string(b) != `{"rule":"2.2.2","severity":"LOW","original_severity":"HIGH","message":""}` {
+		t.Errorf("unexpected encoding: %s", b)
+	}
+
+	if err := json.Unmarshal([]byte(`{"severity":"SEVERE"}`), &r); !errors.Is(err, ErrInvalidSeverity) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := json.Marshal(Recommendation{Severity: "SEVERE"}); !errors.Is(err, ErrInvalidSeverity) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package audit
+
+import "strings"
+
+func newStats(s *Service) Stats {
+	stats := Stats{
+		Endpoints:  len(s.Endpoints),
+		Agents:     len(s.Agents),
+		Components: map[string]ComponentStats{},
+		Modifiers:  map[string]int{},
+		Findings: FindingStats{
+			BySeverity: map[Severity]int{},
+			BySection:  map[string]int{},
+		},
+	}
+
+	for k := range s.Components {
+		c := stats.Components[k]
+		c.Service++
+		stats.Components[k] = c
+	}
+
+	for _, e := range s.Endpoints {
+		for k := range e.Components {
+			c := stats.Components[k]
+			c.Endpoint++
+			stats.Components[k] = c
+		}
+		stats.addModifiers(e.Components)
+		stats.addBackends(e.Backends)
+	}
+
+	for _, a := range s.Agents {
+		for k := range a.Components {
+			c := stats.Components[k]
+			c.Agent++
+			stats.Components[k] = c
+		}
+		stats.addBackends(a.Backends)
+	}
+
+	return stats
+}
+
+func (s *Stats) addBackends(bs []Backend) {
+	s.Backends += len(bs)
+	for _, b := range bs {
+		for k := range b.Components {
+			c := s.Components[k]
+			c.Backend++
+			s.Components[k] = c
+		}
+		s.addModifiers(b.Components)
+	}
+}
+
+func (s *Stats) addModifiers(c Component) {
+	v := c[responseBodyModifierNamespace]
+	for i, kind := range responseBodyModifierKinds {
+		if i+1 < len(v) && v[i+1] > 0 {
+			s.Modifiers[kind] += v[i+1]
+		}
+	}
+}
+
+func (s *Stats) addFinding(r Recommendation) {
+	s.Findings.Total++
+	s.Findings.Locations += len(r.Locations)
+	s.Findings.BySeverity[r.Severity]++
+	s.Findings.BySection[ruleSection(r.Rule)]++
+}
+
+// ruleSection returns the section of the rule set the rule id belongs to
+func ruleSection(id string) string {
+	section, _, _ := strings.Cut(id, ".")
+	return section
+}
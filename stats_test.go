@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/luraproject/lura/v2/config"
+)
+
+func TestAudit_stats(t *testing.T) {
+	cfg, err := config.NewParser().Parse("./tests/example1.json")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	cfg.Normalize()
+
+	result, err := Audit(&cfg, []string{"1.1.1", "1.1.2"}, []string{string(SeverityCritical), string(SeverityHigh), string(SeverityMedium)})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	stats := result.Stats
+
+	if stats.Endpoints != 13 {
+		t.Errorf("unexpected number of endpoints. have: %d, want: 13", stats.Endpoints)
+	}
+	if stats.Backends != 15 {
+		t.Errorf("unexpected number of backends. have: %d, want: 15", stats.Backends)
+	}
+	if stats.Agents != 0 {
+		t.Errorf("unexpected number of agents. have: %d, want: 0", stats.Agents)
+	}
+
+	if c := stats.Components["ai/mcp"]; c != (ComponentStats{Service: 1, Endpoint: 4}) {
+		t.Errorf("unexpected ai/mcp usage: %+v", c)
+	}
+	if c := stats.Components["ai/llm"]; c != (ComponentStats{Backend: 4}) {
+		t.Errorf("unexpected ai/llm usage: %+v", c)
+	}
+
+	for kind, want := range map[string]int{"regexp": 2, "literal": 0, "upper": 1, "lower": 1, "trim": 1} {
+		if have := stats.Modifiers[kind]; have != want {
+			t.Errorf("unexpected number of %s modifiers. have: %d, want: %d", kind, have, want)
+		}
+	}
+
+	if stats.Findings.Total != len(result.Recommendations) {
+		t.Errorf("unexpected number of findings. have: %d, want: %d", stats.Findings.Total, len(result.Recommendations))
+	}
+	if stats.Findings.BySeverity[SeverityCritical] != 2 {
+		t.Errorf("unexpected number of critical findings. have: %d, want: 2", stats.Findings.BySeverity[SeverityCritical])
+	}
+	if stats.Findings.BySeverity[SeverityLow] != 0 {
+		t.Errorf("unexpected number of low findings. have: %d, want: 0", stats.Findings.BySeverity[SeverityLow])
+	}
+	if stats.Findings.BySection["1"] != 0 {
+		t.Errorf("unexpected number of findings in section 1. have: %d, want: 0", stats.Findings.BySection["1"])
+	}
+
+	rules := stats.Rules
+	if rules.Total != len(ruleSet) {
+		t.Errorf("unexpected number of rules. have: %d, want: %d", rules.Total, len(ruleSet))
+	}
+	if rules.Ignored != 2 {
+		t.Errorf("unexpected number of ignored rules. have: %d, want: 2", rules.Ignored)
+	}
+	if rules.Evaluated+rules.Ignored+rules.FilteredBySeverity != rules.Total {
+		t.Errorf("the rule counters do not add up: %+v", rules)
+	}
+	if stats.Duration <= 0 {
+		t.Error("the duration of the audit has not been recorded")
+	}
+}
@@ -0,0 +1,176 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/luraproject/lura/v2/config"
+)
+
+// SuppressionNamespace is the extra_config namespace used to acknowledge findings at the
+// service, endpoint, backend and async agent levels. Example:
+//
+//	"extra_config": {
+//	  "audit": {
+//	    "suppress": [
+//	      {"rules": ["2.2.3"], "reason": "internal endpoint", "expires": "2025-12-31"}
+//	    ]
+//	  }
+//	}
+const SuppressionNamespace = "audit"
+
+// ErrInvalidSuppression is returned when a suppression declared in the configuration is malformed
+// or refers to a rule not registered in the Auditor
+var ErrInvalidSuppression = errors.New("invalid suppression")
+
+// Suppression acknowledges the findings of a set of rules located under the element of the
+// configuration where it is declared. Suppressions without expiration never expire
+type Suppression struct {
+	Rules   []string   `json:"rules"`
+	Reason  string     `json:"reason"`
+	Expires *time.Time `json:"expires,omitempty"`
+	Pointer string     `json:"pointer"`
+}
+
+// SuppressedRecommendation is a recommendation whose locations have been acknowledged by a
+// suppression declared in the configuration
+type SuppressedRecommendation struct {
+	Recommendation
+	Suppression Suppression `json:"suppression"`
+}
+
+func (s Suppression) isActive(now time.Time) bool {
+	return s.Expires == nil || now.Before(*s.Expires)
+}
+
+func (s Suppression) covers(id string, l Location) bool {
+	if !slices.Contains(s.Rules, id) {
+		return false
+	}
+	return s.Pointer == "" || l.Pointer == s.Pointer || strings.HasPrefix(l.Pointer, s.Pointer+"/")
+}
+
+// suppress splits the locations of the recommendation between the ones not covered by any
+// of the active suppressions and the ones acknowledged by each suppression
+func suppress(r Recommendation, ss []Suppression, now time.Time) (Recommendation, []SuppressedRecommendation) {
+	var suppressed []SuppressedRecommendation
+	groups := map[int]int{}
+	locations := make([]Location, 0, len(r.Locations))
+
+	for _, l := range r.Locations {
+		i := -1
+		for j, s := range ss {
+			if s.isActive(now) && s.covers(r.Rule, l) {
+				i = j
+				break
+			}
+		}
+		if i < 0 {
+			locations = append(locations, l)
+			continue
+		}
+
+		k, ok := groups[i]
+		if !ok {
+			k = len(suppressed)
+			groups[i] = k
+			sr := SuppressedRecommendation{Recommendation: r, Suppression: ss[i]}
+			sr.Locations = nil
+			suppressed = append(suppressed, sr)
+		}
+		suppressed[k].Locations = append(suppressed[k].Locations, l)
+	}
+
+	r.Locations = locations
+	return r, suppressed
+}
+
+// parseSuppressions collects the suppressions declared in the received configuration
+func parseSuppressions(cfg *config.ServiceConfig) ([]Suppression, error) {
+	var res []Suppression
+	add := func(scope Scope, extra config.ExtraConfig) error {
+		ss, err := parseSuppression(scope, extra)
+		res = append(res, ss...)
+		return err
+	}
+
+	if err := add(serviceScope(), cfg.ExtraConfig); err != nil {
+		return nil, err
+	}
+	for i, e := range cfg.Endpoints {
+		if err := add(endpointScope(i), e.ExtraConfig); err != nil {
+			return nil, err
+		}
+		for j, b := range e.Backend {
+			if err := add(endpointBackendScope(i, j), b.ExtraConfig); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for i, a := range cfg.AsyncAgents {
+		if err := add(agentScope(i), a.ExtraConfig); err != nil {
+			return nil, err
+		}
+		for j, b := range a.Backend {
+			if err := add(agentBackendScope(i, j), b.ExtraConfig); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return res, nil
+}
+
+func parseSuppression(scope Scope, extra config.ExtraConfig) ([]Suppression, error) {
+	v, ok := extra[SuppressionNamespace]
+	if !ok {
+		return nil, nil
+	}
+
+	pointer := Scope(append(scope, "extra_config", SuppressionNamespace)).Pointer()
+	var cfg struct {
+		Suppress []struct {
+			Rules   []string `mapstructure:"rules"`
+			Reason  string   `mapstructure:"reason"`
+			Expires string   `mapstructure:"expires"`
+		} `mapstructure:"suppress"`
+	}
+	if err := mapstructure.Decode(v, &cfg); err != nil {
+		return nil, fmt.Errorf("%w at %s: %s", ErrInvalidSuppression, pointer, err.Error())
+	}
+
+	res := make([]Suppression, 0, len(cfg.Suppress))
+	for i, s := range cfg.Suppress {
+		if len(s.Rules) == 0 {
+			return nil, fmt.Errorf("%w at %s/suppress/%d: no rules declared", ErrInvalidSuppression, pointer, i)
+		}
+		if strings.TrimSpace(s.Reason) == "" {
+			return nil, fmt.Errorf("%w at %s/suppress/%d: missing reason", ErrInvalidSuppression, pointer, i)
+		}
+		suppression := Suppression{
+			Rules:   s.Rules,
+			Reason:  s.Reason,
+			Pointer: scope.Pointer(),
+		}
+		if s.Expires != "" {
+			expires, err := parseExpiration(s.Expires)
+			if err != nil {
+				return nil, fmt.Errorf("%w at %s/suppress/%d: %s", ErrInvalidSuppression, pointer, i, err.Error())
+			}
+			suppression.Expires = &expires
+		}
+		res = append(res, suppression)
+	}
+	return res, nil
+}
+
+// parseExpiration accepts both dates and RFC 3339 timestamps. Dates expire at the end of the day (UTC)
+func parseExpiration(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t.AddDate(0, 0, 1), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
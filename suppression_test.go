@@ -0,0 +1,131 @@
+package audit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/luraproject/lura/v2/config"
+)
+
+func TestRun_suppressions(t *testing.T) {
+	cfg := loadExample1(t)
+	cfg.ExtraConfig[SuppressionNamespace] = map[string]interface{}{
+		"suppress": []interface{}{
+			map[string]interface{}{
+				"rules":  []interface{}{"2.2.2"},
+				"reason": "CORS is handled by the load balancer",
+			},
+		},
+	}
+	cfg.Endpoints[1].ExtraConfig[SuppressionNamespace] = map[string]interface{}{
+		"suppress": []interface{}{
+			map[string]interface{}{
+				"rules":   []interface{}{"2.2.3", "2.1.9"},
+				"reason":  "internal endpoint",
+				"expires": "2026-12-31",
+			},
+			map[string]interface{}{
+				"rules":   []interface{}{"2.2.4"},
+				"reason":  "temporary exception",
+				"expires": "2026-01-01",
+			},
+		},
+	}
+
+	now := func() time.Time { return time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC) }
+	result, err := Run(cfg, WithSections("2"), WithClock(now))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, r := range result.Recommendations {
+		switch r.Rule {
+		case "2.2.2", "2.2.3", "2.1.9":
+			t.Errorf("recommendation %s should be suppressed", r.Rule)
+		}
+	}
+	found := false
+	for _, r := range result.Recommendations {
+		if r.Rule == "2.2.4" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("the expired suppression should not hide the recommendation 2.2.4")
+	}
+
+	if len(result.Suppressed) != 3 {
+		t.Errorf("unexpected number of suppressed recommendations. have: %d, want: 3", len(result.Suppressed))
+		return
+	}
+	for i, expected := range []struct {
+		rule, reason, pointer, location string
+	}{
+		{"2.1.9", "internal endpoint", "/endpoints/1", "/endpoints/1/backend/0/extra_config/backend~1http~1client"},
+		{"2.2.2", "CORS is handled by the load balancer", "", "/extra_config/github_com~1devopsfaith~1krakend-cors"},
+		{"2.2.3", "internal endpoint", "/endpoints/1", "/endpoints/1/input_headers"},
+	} {
+		s := result.Suppressed[i]
+		if s.Rule != expected.rule || s.Suppression.Reason != expected.reason || s.Suppression.Pointer != expected.pointer {
+			t.Errorf("unexpected suppressed recommendation %d: %+v", i, s)
+		}
+		if len(s.Locations) != 1 || s.Locations[0].Pointer != expected.location {
+			t.Errorf("unexpected suppressed locations %d: %+v", i, s.Locations)
+		}
+	}
+	if result.Stats.Findings.Suppressed != 3 {
+		t.Errorf("unexpected number of suppressed findings. have: %d, want: 3", result.Stats.Findings.Suppressed)
+	}
+
+	later := func() time.Time { return time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC) }
+	result, err = Run(cfg, WithSections("2"), WithClock(later))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(result.Suppressed) != 1 || result.Suppressed[0].Rule != "2.2.2" {
+		t.Errorf("unexpected suppressed recommendations after the expiration: %+v", result.Suppressed)
+	}
+}
+
+func TestRun_invalidSuppressions(t *testing.T) {
+	for _, suppression := range []map[string]interface{}{
+		{"rules": []interface{}{"2.2.3"}},
+		{"reason": "no rules"},
+		{"rules": []interface{}{"2.2.3"}, "reason": "bad date", "expires": "next week"},
+		{"rules": "2.2.3", "reason": "bad type"},
+		{"rules": []interface{}{"2.2.3", "2.1.l"}, "reason": "typo"},
+	} {
+		cfg := &config.ServiceConfig{
+			Endpoints: []*config.EndpointConfig{
+				{
+					Endpoint: "/foo",
+					Method:   "GET",
+					Backend:  []*config.Backend{{URLPattern: "/"}},
+					ExtraConfig: config.ExtraConfig{
+						SuppressionNamespace: map[string]interface{}{
+							"suppress": []interface{}{suppression},
+						},
+					},
+				},
+			},
+		}
+		if _, err := Run(cfg); !errors.Is(err, ErrInvalidSuppression) {
+			t.Errorf("unexpected error for %v: %v", suppression, err)
+		}
+	}
+}
+
+func TestRun_suppressionOfUnknownRule(t *testing.T) {
+	cfg := loadExample1(t)
+	cfg.ExtraConfig[SuppressionNamespace] = map[string]interface{}{
+		"suppress": []interface{}{
+			map[string]interface{}{"rules": []interface{}{"2.1.l"}, "reason": "typo"},
+		},
+	}
+	if _, err := Run(cfg); !errors.Is(err, ErrUnknownRule) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}